@@ -0,0 +1,171 @@
+package migrate
+
+import "terralist/pkg/cli"
+
+// Flag name constants. Every database/storage flag from the server
+// command is mirrored here twice, prefixed with from- and to-, so the
+// same value can describe both ends of the migration
+const (
+	ConfigFlag        = "config"
+	LogLevelFlag      = "log-level"
+	HomeDirectoryFlag = "home-directory"
+
+	CheckpointFileFlag = "checkpoint-file"
+
+	FromDatabaseBackendFlag = "from-database-backend"
+	ToDatabaseBackendFlag   = "to-database-backend"
+
+	FromSQLitePathFlag = "from-sqlite-path"
+	ToSQLitePathFlag   = "to-sqlite-path"
+
+	FromPostgreSQLURLFlag      = "from-postgresql-url"
+	FromPostgreSQLUsernameFlag = "from-postgresql-username"
+	FromPostgreSQLPasswordFlag = "from-postgresql-password"
+	FromPostgreSQLHostFlag     = "from-postgresql-host"
+	FromPostgreSQLPortFlag     = "from-postgresql-port"
+	FromPostgreSQLDatabaseFlag = "from-postgresql-database"
+
+	ToPostgreSQLURLFlag      = "to-postgresql-url"
+	ToPostgreSQLUsernameFlag = "to-postgresql-username"
+	ToPostgreSQLPasswordFlag = "to-postgresql-password"
+	ToPostgreSQLHostFlag     = "to-postgresql-host"
+	ToPostgreSQLPortFlag     = "to-postgresql-port"
+	ToPostgreSQLDatabaseFlag = "to-postgresql-database"
+
+	FromMySQLURLFlag      = "from-mysql-url"
+	FromMySQLUsernameFlag = "from-mysql-username"
+	FromMySQLPasswordFlag = "from-mysql-password"
+	FromMySQLHostFlag     = "from-mysql-host"
+	FromMySQLPortFlag     = "from-mysql-port"
+	FromMySQLDatabaseFlag = "from-mysql-database"
+
+	ToMySQLURLFlag      = "to-mysql-url"
+	ToMySQLUsernameFlag = "to-mysql-username"
+	ToMySQLPasswordFlag = "to-mysql-password"
+	ToMySQLHostFlag     = "to-mysql-host"
+	ToMySQLPortFlag     = "to-mysql-port"
+	ToMySQLDatabaseFlag = "to-mysql-database"
+
+	FromStorageResolverFlag = "from-storage-resolver"
+	ToStorageResolverFlag   = "to-storage-resolver"
+
+	FromS3BucketNameFlag      = "from-s3-bucket-name"
+	FromS3BucketRegionFlag    = "from-s3-bucket-region"
+	FromS3AccessKeyIDFlag     = "from-s3-access-key-id"
+	FromS3SecretAccessKeyFlag = "from-s3-secret-access-key"
+
+	ToS3BucketNameFlag      = "to-s3-bucket-name"
+	ToS3BucketRegionFlag    = "to-s3-bucket-region"
+	ToS3AccessKeyIDFlag     = "to-s3-access-key-id"
+	ToS3SecretAccessKeyFlag = "to-s3-secret-access-key"
+
+	FromAzureAccountNameFlag   = "from-azure-account-name"
+	FromAzureAccountKeyFlag    = "from-azure-account-key"
+	FromAzureSASTokenFlag      = "from-azure-sas-token"
+	FromAzureContainerNameFlag = "from-azure-container-name"
+
+	ToAzureAccountNameFlag   = "to-azure-account-name"
+	ToAzureAccountKeyFlag    = "to-azure-account-key"
+	ToAzureSASTokenFlag      = "to-azure-sas-token"
+	ToAzureContainerNameFlag = "to-azure-container-name"
+
+	FromGCSBucketNameFlag      = "from-gcs-bucket-name"
+	FromGCSCredentialsFileFlag = "from-gcs-credentials-file"
+
+	ToGCSBucketNameFlag      = "to-gcs-bucket-name"
+	ToGCSCredentialsFileFlag = "to-gcs-credentials-file"
+)
+
+// flags holds every flag accepted by the migrate command
+var flags = map[string]cli.Flag{
+	ConfigFlag: &cli.StringFlag{
+		Usage: "Path to a config file",
+	},
+	LogLevelFlag: &cli.StringFlag{
+		Usage:        "The verbosity of the logs",
+		DefaultValue: "info",
+	},
+	HomeDirectoryFlag: &cli.StringFlag{
+		Usage:        "The directory Terralist will use to store local data and the checkpoint file",
+		DefaultValue: "~/.terralist.d",
+	},
+	CheckpointFileFlag: &cli.StringFlag{
+		Usage:        "Path to the checkpoint file used to resume an interrupted migration",
+		DefaultValue: "migrate.checkpoint",
+	},
+
+	FromDatabaseBackendFlag: &cli.StringFlag{
+		Usage:    "The database backend to migrate from (sqlite, postgresql, mysql)",
+		Required: true,
+	},
+	ToDatabaseBackendFlag: &cli.StringFlag{
+		Usage:    "The database backend to migrate to (sqlite, postgresql, mysql)",
+		Required: true,
+	},
+
+	FromSQLitePathFlag: &cli.StringFlag{Usage: "The source SQLite database file"},
+	ToSQLitePathFlag:   &cli.StringFlag{Usage: "The destination SQLite database file"},
+
+	FromPostgreSQLURLFlag:      &cli.StringFlag{Usage: "The source PostgreSQL connection URL"},
+	FromPostgreSQLUsernameFlag: &cli.StringFlag{Usage: "The source PostgreSQL username"},
+	FromPostgreSQLPasswordFlag: &cli.StringFlag{Usage: "The source PostgreSQL password"},
+	FromPostgreSQLHostFlag:     &cli.StringFlag{Usage: "The source PostgreSQL hostname"},
+	FromPostgreSQLPortFlag:     &cli.IntFlag{Usage: "The source PostgreSQL port", DefaultValue: 5432},
+	FromPostgreSQLDatabaseFlag: &cli.StringFlag{Usage: "The source PostgreSQL database name"},
+
+	ToPostgreSQLURLFlag:      &cli.StringFlag{Usage: "The destination PostgreSQL connection URL"},
+	ToPostgreSQLUsernameFlag: &cli.StringFlag{Usage: "The destination PostgreSQL username"},
+	ToPostgreSQLPasswordFlag: &cli.StringFlag{Usage: "The destination PostgreSQL password"},
+	ToPostgreSQLHostFlag:     &cli.StringFlag{Usage: "The destination PostgreSQL hostname"},
+	ToPostgreSQLPortFlag:     &cli.IntFlag{Usage: "The destination PostgreSQL port", DefaultValue: 5432},
+	ToPostgreSQLDatabaseFlag: &cli.StringFlag{Usage: "The destination PostgreSQL database name"},
+
+	FromMySQLURLFlag:      &cli.StringFlag{Usage: "The source MySQL connection URL"},
+	FromMySQLUsernameFlag: &cli.StringFlag{Usage: "The source MySQL username"},
+	FromMySQLPasswordFlag: &cli.StringFlag{Usage: "The source MySQL password"},
+	FromMySQLHostFlag:     &cli.StringFlag{Usage: "The source MySQL hostname"},
+	FromMySQLPortFlag:     &cli.IntFlag{Usage: "The source MySQL port", DefaultValue: 3306},
+	FromMySQLDatabaseFlag: &cli.StringFlag{Usage: "The source MySQL database name"},
+
+	ToMySQLURLFlag:      &cli.StringFlag{Usage: "The destination MySQL connection URL"},
+	ToMySQLUsernameFlag: &cli.StringFlag{Usage: "The destination MySQL username"},
+	ToMySQLPasswordFlag: &cli.StringFlag{Usage: "The destination MySQL password"},
+	ToMySQLHostFlag:     &cli.StringFlag{Usage: "The destination MySQL hostname"},
+	ToMySQLPortFlag:     &cli.IntFlag{Usage: "The destination MySQL port", DefaultValue: 3306},
+	ToMySQLDatabaseFlag: &cli.StringFlag{Usage: "The destination MySQL database name"},
+
+	FromStorageResolverFlag: &cli.StringFlag{
+		Usage:    "The storage resolver to migrate from (proxy, local, s3, azure, gcs)",
+		Required: true,
+	},
+	ToStorageResolverFlag: &cli.StringFlag{
+		Usage:    "The storage resolver to migrate to (proxy, local, s3, azure, gcs)",
+		Required: true,
+	},
+
+	FromS3BucketNameFlag:      &cli.StringFlag{Usage: "The source S3 bucket name"},
+	FromS3BucketRegionFlag:    &cli.StringFlag{Usage: "The source S3 bucket region"},
+	FromS3AccessKeyIDFlag:     &cli.StringFlag{Usage: "The source S3 access key ID"},
+	FromS3SecretAccessKeyFlag: &cli.StringFlag{Usage: "The source S3 secret access key"},
+
+	ToS3BucketNameFlag:      &cli.StringFlag{Usage: "The destination S3 bucket name"},
+	ToS3BucketRegionFlag:    &cli.StringFlag{Usage: "The destination S3 bucket region"},
+	ToS3AccessKeyIDFlag:     &cli.StringFlag{Usage: "The destination S3 access key ID"},
+	ToS3SecretAccessKeyFlag: &cli.StringFlag{Usage: "The destination S3 secret access key"},
+
+	FromAzureAccountNameFlag:   &cli.StringFlag{Usage: "The source Azure Blob Storage account name"},
+	FromAzureAccountKeyFlag:    &cli.StringFlag{Usage: "The source Azure Blob Storage account key"},
+	FromAzureSASTokenFlag:      &cli.StringFlag{Usage: "A SAS token, used instead of the source account key"},
+	FromAzureContainerNameFlag: &cli.StringFlag{Usage: "The source Azure Blob Storage container name"},
+
+	ToAzureAccountNameFlag:   &cli.StringFlag{Usage: "The destination Azure Blob Storage account name"},
+	ToAzureAccountKeyFlag:    &cli.StringFlag{Usage: "The destination Azure Blob Storage account key"},
+	ToAzureSASTokenFlag:      &cli.StringFlag{Usage: "A SAS token, used instead of the destination account key"},
+	ToAzureContainerNameFlag: &cli.StringFlag{Usage: "The destination Azure Blob Storage container name"},
+
+	FromGCSBucketNameFlag:      &cli.StringFlag{Usage: "The source Google Cloud Storage bucket name"},
+	FromGCSCredentialsFileFlag: &cli.StringFlag{Usage: "Path to the source service account JSON key file"},
+
+	ToGCSBucketNameFlag:      &cli.StringFlag{Usage: "The destination Google Cloud Storage bucket name"},
+	ToGCSCredentialsFileFlag: &cli.StringFlag{Usage: "Path to the destination service account JSON key file"},
+}
@@ -0,0 +1,703 @@
+package migrate
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"terralist/pkg/cli"
+	"terralist/pkg/database"
+	dbFactory "terralist/pkg/database/factory"
+	"terralist/pkg/database/mysql"
+	"terralist/pkg/database/postgresql"
+	"terralist/pkg/database/sqlite"
+	"terralist/pkg/storage/resolver"
+	"terralist/pkg/storage/resolver/azure"
+	storageFactory "terralist/pkg/storage/resolver/factory"
+	"terralist/pkg/storage/resolver/gcs"
+	"terralist/pkg/storage/resolver/local"
+	"terralist/pkg/storage/resolver/proxy"
+	"terralist/pkg/storage/resolver/s3"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Command is an abstraction for the migrate command
+type Command struct {
+	Viper *viper.Viper
+
+	RunningMode string
+
+	SilenceOutput bool
+}
+
+// checkpoint tracks migration progress so a failed run can be resumed
+// instead of starting over
+type checkpoint struct {
+	// RowsCopied is the number of database rows already migrated
+	RowsCopied int `json:"rows_copied"`
+
+	// ArchivesCopied is the number of module/provider archives already
+	// migrated
+	ArchivesCopied int `json:"archives_copied"`
+}
+
+func (s *Command) Init() *cobra.Command {
+	c := &cobra.Command{
+		Use:           "migrate",
+		Short:         "Migrates Terralist data between database and storage backends",
+		Long:          "Copies rows and module/provider archives from one database/storage backend pair to another.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PreRunE: s.withErrPrint(func(cmd *cobra.Command, args []string) error {
+			return s.preRun()
+		}),
+		RunE: s.withErrPrint(func(cmd *cobra.Command, args []string) error {
+			return s.run()
+		}),
+	}
+
+	s.Viper.SetEnvPrefix("TERRALIST")
+	s.Viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	s.Viper.AutomaticEnv()
+	s.Viper.SetTypeByDefaultValue(true)
+
+	c.SetUsageTemplate(cli.UsageTmpl(flags))
+	c.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		s.printErr(err)
+		return err
+	})
+
+	for name, f := range flags {
+		usage := f.Format() + "\n"
+
+		if fg, ok := f.(*cli.StringFlag); ok {
+			c.Flags().String(name, fg.DefaultValue, usage)
+		} else if fg, ok := f.(*cli.IntFlag); ok {
+			c.Flags().Int(name, fg.DefaultValue, usage)
+		} else if fg, ok := f.(*cli.BoolFlag); ok {
+			c.Flags().Bool(name, fg.DefaultValue, usage)
+		}
+
+		if f.IsHidden() {
+			_ = c.Flags().MarkHidden(name)
+		}
+
+		_ = s.Viper.BindPFlag(name, c.Flags().Lookup(name))
+	}
+
+	return c
+}
+
+func (s *Command) preRun() error {
+	configFile := s.Viper.GetString(ConfigFlag)
+
+	if configFile != "" {
+		s.Viper.SetConfigFile(configFile)
+		if err := s.Viper.ReadInConfig(); err != nil {
+			return errors.Wrapf(err, "invalid config: reading %s", configFile)
+		}
+	}
+
+	return nil
+}
+
+func (s *Command) run() error {
+	var raw map[string]any
+
+	if err := s.Viper.Unmarshal(&raw); err != nil {
+		return err
+	}
+
+	for k, v := range raw {
+		if _, ok := flags[k]; ok {
+			if !s.Viper.IsSet(k) {
+				_ = flags[k].Set(nil)
+
+				continue
+			}
+
+			if err := flags[k].Set(v); err != nil {
+				return fmt.Errorf("could not unpack flags: %v", err)
+			}
+		}
+	}
+
+	for k, v := range flags {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("could not validate %v: %v", k, err)
+		}
+	}
+
+	switch flags[LogLevelFlag].(*cli.StringFlag).Value {
+	case "trace":
+		zerolog.SetGlobalLevel(zerolog.TraceLevel)
+	case "debug":
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	case "info":
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	case "warn":
+		zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	case "error":
+		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+	}
+
+	homeDirClean := filepath.Clean(flags[HomeDirectoryFlag].(*cli.StringFlag).Value)
+	if strings.HasPrefix(homeDirClean, "~") {
+		userHomeDir, _ := os.UserHomeDir()
+		homeDirClean = fmt.Sprintf("%s%s", userHomeDir, homeDirClean[1:])
+	}
+
+	homeDir, err := filepath.Abs(homeDirClean)
+	if err != nil {
+		return fmt.Errorf("invalid value for home directory: %v", err)
+	}
+
+	if err := os.MkdirAll(homeDir, os.ModePerm); err != nil {
+		return fmt.Errorf("could not create the home directory: %v", err)
+	}
+
+	fromDB, err := newDatabase(
+		flags[FromDatabaseBackendFlag].(*cli.StringFlag).Value,
+		"from",
+	)
+	if err != nil {
+		return errors.Wrap(err, "opening source database")
+	}
+
+	toDB, err := newDatabase(
+		flags[ToDatabaseBackendFlag].(*cli.StringFlag).Value,
+		"to",
+	)
+	if err != nil {
+		return errors.Wrap(err, "opening destination database")
+	}
+
+	fromRes, err := newResolver(
+		flags[FromStorageResolverFlag].(*cli.StringFlag).Value,
+		"from",
+		homeDir,
+	)
+	if err != nil {
+		return errors.Wrap(err, "opening source storage resolver")
+	}
+
+	toRes, err := newResolver(
+		flags[ToStorageResolverFlag].(*cli.StringFlag).Value,
+		"to",
+		homeDir,
+	)
+	if err != nil {
+		return errors.Wrap(err, "opening destination storage resolver")
+	}
+
+	checkpointPath := filepath.Join(homeDir, filepath.Base(flags[CheckpointFileFlag].(*cli.StringFlag).Value))
+
+	cp, err := loadCheckpoint(checkpointPath)
+	if err != nil {
+		return errors.Wrap(err, "loading checkpoint")
+	}
+
+	return s.migrate(fromDB, toDB, fromRes, toRes, checkpointPath, cp)
+}
+
+// migrate copies rows and archives from the source backends to the
+// destination backends, persisting cp to checkpointPath after each unit
+// of work so an interrupted run can resume instead of restarting
+func (s *Command) migrate(
+	fromDB database.Engine,
+	toDB database.Engine,
+	fromRes resolver.Resolver,
+	toRes resolver.Resolver,
+	checkpointPath string,
+	cp *checkpoint,
+) error {
+	if err := fromDB.Connect(); err != nil {
+		return errors.Wrap(err, "connecting to source database")
+	}
+	defer fromDB.Close()
+
+	if err := toDB.Connect(); err != nil {
+		return errors.Wrap(err, "connecting to destination database")
+	}
+	defer toDB.Close()
+
+	log.Info().
+		Int("rows_copied", cp.RowsCopied).
+		Int("archives_copied", cp.ArchivesCopied).
+		Msg("resuming migration from checkpoint")
+
+	if err := copyRows(fromDB.DB(), toDB.DB(), cp, checkpointPath); err != nil {
+		return errors.Wrap(err, "copying rows")
+	}
+
+	if err := copyArchives(fromRes, toRes, cp, checkpointPath); err != nil {
+		return errors.Wrap(err, "copying archives")
+	}
+
+	return nil
+}
+
+// copyRows copies every row of every table in fromDB into toDB, in a
+// stable table order, skipping the first cp.RowsCopied rows already
+// migrated by a previous run and persisting cp to checkpointPath after
+// each row so an interrupted run can resume. Rows within a table are
+// read in a deterministic order (the table's primary key, or every
+// column if it has none) so that the skip-by-position on resume lines
+// up with what a previous run actually copied
+func copyRows(fromDB *sql.DB, toDB *sql.DB, cp *checkpoint, checkpointPath string) error {
+	tables, dialect, err := listTables(fromDB)
+	if err != nil {
+		return errors.Wrap(err, "listing source tables")
+	}
+
+	copied := 0
+	for _, table := range tables {
+		orderColumns, err := rowOrderColumns(fromDB, table, dialect)
+		if err != nil {
+			return errors.Wrapf(err, "determining row order for table %s", table)
+		}
+
+		query := fmt.Sprintf("SELECT * FROM %s", table)
+		if len(orderColumns) > 0 {
+			query += " ORDER BY " + strings.Join(orderColumns, ", ")
+		}
+
+		rows, err := fromDB.Query(query)
+		if err != nil {
+			return errors.Wrapf(err, "reading table %s", table)
+		}
+
+		if err := copyTableRows(rows, toDB, table, dialect, cp, checkpointPath, &copied); err != nil {
+			rows.Close()
+			return err
+		}
+
+		rows.Close()
+	}
+
+	return nil
+}
+
+// copyTableRows copies every row of rows into table in toDB, skipping
+// rows until *copied reaches cp.RowsCopied
+func copyTableRows(
+	rows *sql.Rows,
+	toDB *sql.DB,
+	table string,
+	dialect string,
+	cp *checkpoint,
+	checkpointPath string,
+	copied *int,
+) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		if dialect == "postgresql" {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		} else {
+			placeholders[i] = "?"
+		}
+	}
+
+	insertSQL := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "),
+	)
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if *copied < cp.RowsCopied {
+			*copied++
+			continue
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return errors.Wrapf(err, "scanning a row from %s", table)
+		}
+
+		if _, err := toDB.Exec(insertSQL, values...); err != nil {
+			return errors.Wrapf(err, "writing a row to %s", table)
+		}
+
+		*copied++
+		cp.RowsCopied = *copied
+
+		if err := saveCheckpoint(checkpointPath, cp); err != nil {
+			return errors.Wrap(err, "saving checkpoint")
+		}
+	}
+
+	return rows.Err()
+}
+
+// listTables returns every user table in db, in a stable order, along
+// with the dialect ("sqlite", "postgresql" or "mysql") it was found under
+func listTables(db *sql.DB) ([]string, string, error) {
+	queries := map[string]string{
+		"sqlite":     "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name",
+		"postgresql": "SELECT table_name FROM information_schema.tables WHERE table_schema = 'public' ORDER BY table_name",
+		"mysql":      "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() ORDER BY table_name",
+	}
+
+	var lastErr error
+	for _, dialect := range []string{"sqlite", "postgresql", "mysql"} {
+		rows, err := db.Query(queries[dialect])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var tables []string
+		for rows.Next() {
+			var table string
+			if err := rows.Scan(&table); err != nil {
+				rows.Close()
+				return nil, "", err
+			}
+
+			tables = append(tables, table)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			lastErr = err
+			continue
+		}
+
+		return tables, dialect, nil
+	}
+
+	return nil, "", fmt.Errorf("could not determine the database dialect: %v", lastErr)
+}
+
+// rowOrderColumns returns the columns copyRows should ORDER BY when
+// reading table, so repeated runs over unchanged data see rows in the
+// same position. It prefers the table's primary key; tables without one
+// fall back to every column, which is still deterministic for a given
+// snapshot of the data
+func rowOrderColumns(db *sql.DB, table string, dialect string) ([]string, error) {
+	pk, err := primaryKeyColumns(db, table, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pk) > 0 {
+		return pk, nil
+	}
+
+	probe, err := db.Query(fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", table))
+	if err != nil {
+		return nil, err
+	}
+	defer probe.Close()
+
+	return probe.Columns()
+}
+
+// primaryKeyColumns returns table's primary key columns, in ordinal
+// order, or nil if the table has none
+func primaryKeyColumns(db *sql.DB, table string, dialect string) ([]string, error) {
+	if dialect == "sqlite" {
+		return sqlitePrimaryKeyColumns(db, table)
+	}
+
+	queries := map[string]string{
+		"postgresql": `
+			SELECT a.attname
+			FROM pg_index i
+			JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+			WHERE i.indrelid = $1::regclass AND i.indisprimary
+			ORDER BY array_position(i.indkey, a.attnum)
+		`,
+		"mysql": `
+			SELECT column_name
+			FROM information_schema.statistics
+			WHERE table_schema = DATABASE() AND table_name = ? AND index_name = 'PRIMARY'
+			ORDER BY seq_in_index
+		`,
+	}
+
+	query, ok := queries[dialect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported dialect %s", dialect)
+	}
+
+	rows, err := db.Query(query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
+// sqlitePrimaryKeyColumns returns table's primary key columns, in
+// ordinal order, using PRAGMA table_info since sqlite has no
+// information_schema
+func sqlitePrimaryKeyColumns(db *sql.DB, table string) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type pkColumn struct {
+		name  string
+		order int
+	}
+
+	var pkColumns []pkColumn
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, columnType string
+		var defaultValue any
+
+		if err := rows.Scan(&cid, &name, &columnType, &notnull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+
+		if pk > 0 {
+			pkColumns = append(pkColumns, pkColumn{name: name, order: pk})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pkColumns, func(i, j int) bool { return pkColumns[i].order < pkColumns[j].order })
+
+	columns := make([]string, len(pkColumns))
+	for i, c := range pkColumns {
+		columns[i] = c.name
+	}
+
+	return columns, nil
+}
+
+// copyArchives copies every archive fromRes knows about into toRes,
+// skipping the first cp.ArchivesCopied archives already migrated by a
+// previous run and persisting cp to checkpointPath after each archive.
+// Resolvers that can't enumerate or read back their own content, such as
+// the proxy resolver, are skipped: there's nothing to migrate
+func copyArchives(fromRes resolver.Resolver, toRes resolver.Resolver, cp *checkpoint, checkpointPath string) error {
+	lister, ok := fromRes.(resolver.Lister)
+	if !ok {
+		log.Warn().Msg("source storage resolver cannot enumerate archives, skipping archive migration")
+		return nil
+	}
+
+	getter, ok := fromRes.(resolver.Getter)
+	if !ok {
+		return fmt.Errorf("source storage resolver cannot read back archive contents")
+	}
+
+	keys, err := lister.List()
+	if err != nil {
+		return errors.Wrap(err, "listing source archives")
+	}
+
+	for i, key := range keys {
+		if i < cp.ArchivesCopied {
+			continue
+		}
+
+		content, err := getter.Get(key)
+		if err != nil {
+			return errors.Wrapf(err, "reading archive %s", key)
+		}
+
+		if err := toRes.Store(key, content); err != nil {
+			return errors.Wrapf(err, "writing archive %s", key)
+		}
+
+		cp.ArchivesCopied = i + 1
+
+		if err := saveCheckpoint(checkpointPath, cp); err != nil {
+			return errors.Wrap(err, "saving checkpoint")
+		}
+	}
+
+	return nil
+}
+
+func loadCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %v", path, err)
+	}
+
+	return cp, nil
+}
+
+func saveCheckpoint(path string, cp *checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// newDatabase opens a database.Engine for the given side ("from" or "to")
+// using the same factory the server command relies on
+func newDatabase(backend string, side string) (database.Engine, error) {
+	isFrom := side == "from"
+
+	switch backend {
+	case "sqlite":
+		path := ToSQLitePathFlag
+		if isFrom {
+			path = FromSQLitePathFlag
+		}
+
+		return dbFactory.NewDatabase(database.SQLITE, &sqlite.Config{
+			Path: flags[path].(*cli.StringFlag).Value,
+		})
+	case "postgresql":
+		url, username, password, host, port, name := ToPostgreSQLURLFlag, ToPostgreSQLUsernameFlag,
+			ToPostgreSQLPasswordFlag, ToPostgreSQLHostFlag, ToPostgreSQLPortFlag, ToPostgreSQLDatabaseFlag
+		if isFrom {
+			url, username, password, host, port, name = FromPostgreSQLURLFlag, FromPostgreSQLUsernameFlag,
+				FromPostgreSQLPasswordFlag, FromPostgreSQLHostFlag, FromPostgreSQLPortFlag, FromPostgreSQLDatabaseFlag
+		}
+
+		return dbFactory.NewDatabase(database.POSTGRESQL, &postgresql.Config{
+			URL:      flags[url].(*cli.StringFlag).Value,
+			Username: flags[username].(*cli.StringFlag).Value,
+			Password: flags[password].(*cli.StringFlag).Value,
+			Hostname: flags[host].(*cli.StringFlag).Value,
+			Port:     flags[port].(*cli.IntFlag).Value,
+			Name:     flags[name].(*cli.StringFlag).Value,
+		})
+	case "mysql":
+		url, username, password, host, port, name := ToMySQLURLFlag, ToMySQLUsernameFlag,
+			ToMySQLPasswordFlag, ToMySQLHostFlag, ToMySQLPortFlag, ToMySQLDatabaseFlag
+		if isFrom {
+			url, username, password, host, port, name = FromMySQLURLFlag, FromMySQLUsernameFlag,
+				FromMySQLPasswordFlag, FromMySQLHostFlag, FromMySQLPortFlag, FromMySQLDatabaseFlag
+		}
+
+		return dbFactory.NewDatabase(database.MYSQL, &mysql.Config{
+			URL:      flags[url].(*cli.StringFlag).Value,
+			Username: flags[username].(*cli.StringFlag).Value,
+			Password: flags[password].(*cli.StringFlag).Value,
+			Hostname: flags[host].(*cli.StringFlag).Value,
+			Port:     flags[port].(*cli.IntFlag).Value,
+			Name:     flags[name].(*cli.StringFlag).Value,
+		})
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", backend)
+	}
+}
+
+// newResolver opens a resolver.Resolver for the given side ("from" or
+// "to") using the same factory the server command relies on
+func newResolver(kind string, side string, homeDir string) (resolver.Resolver, error) {
+	isFrom := side == "from"
+
+	switch kind {
+	case "proxy":
+		return storageFactory.NewResolver(resolver.PROXY, &proxy.Config{})
+	case "local":
+		return storageFactory.NewResolver(resolver.LOCAL, &local.Config{
+			HomeDirectory: homeDir,
+		})
+	case "s3":
+		bucket, region, accessKeyID, secretAccessKey := ToS3BucketNameFlag, ToS3BucketRegionFlag,
+			ToS3AccessKeyIDFlag, ToS3SecretAccessKeyFlag
+		if isFrom {
+			bucket, region, accessKeyID, secretAccessKey = FromS3BucketNameFlag, FromS3BucketRegionFlag,
+				FromS3AccessKeyIDFlag, FromS3SecretAccessKeyFlag
+		}
+
+		return storageFactory.NewResolver(resolver.S3, &s3.Config{
+			HomeDirectory:   homeDir,
+			BucketName:      flags[bucket].(*cli.StringFlag).Value,
+			BucketRegion:    flags[region].(*cli.StringFlag).Value,
+			AccessKeyID:     flags[accessKeyID].(*cli.StringFlag).Value,
+			SecretAccessKey: flags[secretAccessKey].(*cli.StringFlag).Value,
+		})
+	case "azure":
+		accountName, accountKey, sasToken, containerName := ToAzureAccountNameFlag, ToAzureAccountKeyFlag,
+			ToAzureSASTokenFlag, ToAzureContainerNameFlag
+		if isFrom {
+			accountName, accountKey, sasToken, containerName = FromAzureAccountNameFlag, FromAzureAccountKeyFlag,
+				FromAzureSASTokenFlag, FromAzureContainerNameFlag
+		}
+
+		return storageFactory.NewResolver(resolver.AZURE, &azure.Config{
+			HomeDirectory: homeDir,
+			AccountName:   flags[accountName].(*cli.StringFlag).Value,
+			AccountKey:    flags[accountKey].(*cli.StringFlag).Value,
+			SASToken:      flags[sasToken].(*cli.StringFlag).Value,
+			ContainerName: flags[containerName].(*cli.StringFlag).Value,
+		})
+	case "gcs":
+		bucket, credentialsFile := ToGCSBucketNameFlag, ToGCSCredentialsFileFlag
+		if isFrom {
+			bucket, credentialsFile = FromGCSBucketNameFlag, FromGCSCredentialsFileFlag
+		}
+
+		return storageFactory.NewResolver(resolver.GCS, &gcs.Config{
+			HomeDirectory:   homeDir,
+			BucketName:      flags[bucket].(*cli.StringFlag).Value,
+			CredentialsFile: flags[credentialsFile].(*cli.StringFlag).Value,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage resolver %q", kind)
+	}
+}
+
+// withErrPrint prints out any cmd errors to stderr
+func (s *Command) withErrPrint(f func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		err := f(cmd, args)
+		if err != nil && !s.SilenceOutput {
+			s.printErr(err)
+		}
+		return err
+	}
+}
+
+// printErr prints err to stderr using a red terminal color
+func (s *Command) printErr(err error) {
+	log.Error().AnErr("error", err).Send()
+}
@@ -0,0 +1,222 @@
+package migrate
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"terralist/pkg/storage/resolver/local"
+	"terralist/pkg/storage/resolver/proxy"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("could not open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestCopyRows_CopiesEveryRowAndPersistsCheckpoint(t *testing.T) {
+	fromDB := openTestDB(t)
+	toDB := openTestDB(t)
+
+	if _, err := fromDB.Exec("CREATE TABLE modules (id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("could not create source table: %v", err)
+	}
+	if _, err := toDB.Exec("CREATE TABLE modules (id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("could not create destination table: %v", err)
+	}
+
+	for i, name := range []string{"foo", "bar", "baz"} {
+		if _, err := fromDB.Exec("INSERT INTO modules (id, name) VALUES (?, ?)", i, name); err != nil {
+			t.Fatalf("could not insert row: %v", err)
+		}
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "migrate.checkpoint")
+	cp := &checkpoint{}
+
+	if err := copyRows(fromDB, toDB, cp, checkpointPath); err != nil {
+		t.Fatalf("copyRows() returned error: %v", err)
+	}
+
+	if cp.RowsCopied != 3 {
+		t.Fatalf("expected 3 rows copied, got %d", cp.RowsCopied)
+	}
+
+	var count int
+	if err := toDB.QueryRow("SELECT COUNT(*) FROM modules").Scan(&count); err != nil {
+		t.Fatalf("could not count destination rows: %v", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("expected 3 rows in destination, got %d", count)
+	}
+
+	persisted, err := loadCheckpoint(checkpointPath)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() returned error: %v", err)
+	}
+
+	if persisted.RowsCopied != 3 {
+		t.Fatalf("expected checkpoint file to record 3 rows copied, got %d", persisted.RowsCopied)
+	}
+}
+
+func TestCopyRows_ResumesFromCheckpoint(t *testing.T) {
+	fromDB := openTestDB(t)
+	toDB := openTestDB(t)
+
+	if _, err := fromDB.Exec("CREATE TABLE modules (id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("could not create source table: %v", err)
+	}
+	if _, err := toDB.Exec("CREATE TABLE modules (id INTEGER, name TEXT)"); err != nil {
+		t.Fatalf("could not create destination table: %v", err)
+	}
+
+	for i, name := range []string{"foo", "bar"} {
+		if _, err := fromDB.Exec("INSERT INTO modules (id, name) VALUES (?, ?)", i, name); err != nil {
+			t.Fatalf("could not insert row: %v", err)
+		}
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "migrate.checkpoint")
+	cp := &checkpoint{RowsCopied: 1}
+
+	if err := copyRows(fromDB, toDB, cp, checkpointPath); err != nil {
+		t.Fatalf("copyRows() returned error: %v", err)
+	}
+
+	var count int
+	if err := toDB.QueryRow("SELECT COUNT(*) FROM modules").Scan(&count); err != nil {
+		t.Fatalf("could not count destination rows: %v", err)
+	}
+
+	if count != 1 {
+		t.Fatalf("expected the already-migrated row to be skipped, got %d rows", count)
+	}
+
+	var id int
+	var name string
+	if err := toDB.QueryRow("SELECT id, name FROM modules").Scan(&id, &name); err != nil {
+		t.Fatalf("could not read destination row: %v", err)
+	}
+
+	if id != 1 || name != "bar" {
+		t.Fatalf("expected row (1, \"bar\") to resume the migration, got (%d, %q)", id, name)
+	}
+}
+
+func TestCopyRows_OrdersByPrimaryKeyForStableResume(t *testing.T) {
+	fromDB := openTestDB(t)
+	toDB := openTestDB(t)
+
+	if _, err := fromDB.Exec("CREATE TABLE modules (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("could not create source table: %v", err)
+	}
+	if _, err := toDB.Exec("CREATE TABLE modules (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("could not create destination table: %v", err)
+	}
+
+	// Insert out of id order, so a plain "SELECT * FROM modules" without an
+	// ORDER BY would not reliably return rows sorted by id
+	rows := []struct {
+		id   int
+		name string
+	}{
+		{3, "baz"},
+		{1, "foo"},
+		{2, "bar"},
+	}
+	for _, r := range rows {
+		if _, err := fromDB.Exec("INSERT INTO modules (id, name) VALUES (?, ?)", r.id, r.name); err != nil {
+			t.Fatalf("could not insert row: %v", err)
+		}
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "migrate.checkpoint")
+	cp := &checkpoint{RowsCopied: 2}
+
+	if err := copyRows(fromDB, toDB, cp, checkpointPath); err != nil {
+		t.Fatalf("copyRows() returned error: %v", err)
+	}
+
+	var id int
+	var name string
+	if err := toDB.QueryRow("SELECT id, name FROM modules").Scan(&id, &name); err != nil {
+		t.Fatalf("could not read destination row: %v", err)
+	}
+
+	if id != 3 || name != "baz" {
+		t.Fatalf("expected row (3, \"baz\") to resume the migration by primary key order, got (%d, %q)", id, name)
+	}
+}
+
+func TestCopyArchives_CopiesEveryArchiveAndSkipsAlreadyMigrated(t *testing.T) {
+	fromRes, err := local.New(&local.Config{HomeDirectory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("could not create source resolver: %v", err)
+	}
+
+	toRes, err := local.New(&local.Config{HomeDirectory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("could not create destination resolver: %v", err)
+	}
+
+	if err := fromRes.Store("modules/bar/1.0.0.zip", []byte("bar")); err != nil {
+		t.Fatalf("could not seed source archive: %v", err)
+	}
+	if err := fromRes.Store("modules/foo/1.0.0.zip", []byte("foo")); err != nil {
+		t.Fatalf("could not seed source archive: %v", err)
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "migrate.checkpoint")
+	cp := &checkpoint{ArchivesCopied: 1}
+
+	if err := copyArchives(fromRes, toRes, cp, checkpointPath); err != nil {
+		t.Fatalf("copyArchives() returned error: %v", err)
+	}
+
+	if cp.ArchivesCopied != 2 {
+		t.Fatalf("expected 2 archives copied, got %d", cp.ArchivesCopied)
+	}
+
+	content, err := toRes.Get("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("expected the second archive to have been migrated: %v", err)
+	}
+
+	if string(content) != "foo" {
+		t.Fatalf("expected foo content, got %q", content)
+	}
+
+	if _, err := toRes.Get("modules/bar/1.0.0.zip"); err == nil {
+		t.Fatal("expected the already-migrated archive to have been skipped")
+	}
+}
+
+func TestCopyArchives_SkipsWhenSourceResolverCannotEnumerate(t *testing.T) {
+	fromRes, err := proxy.New(&proxy.Config{})
+	if err != nil {
+		t.Fatalf("could not create source resolver: %v", err)
+	}
+
+	toRes, err := local.New(&local.Config{HomeDirectory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("could not create destination resolver: %v", err)
+	}
+
+	checkpointPath := filepath.Join(t.TempDir(), "migrate.checkpoint")
+	cp := &checkpoint{}
+
+	if err := copyArchives(fromRes, toRes, cp, checkpointPath); err != nil {
+		t.Fatalf("expected no error for a source resolver that can't enumerate archives, got %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package server
+
+import "terralist/pkg/cli"
+
+// Flag name constants
+const (
+	ConfigFlag   = "config"
+	LogLevelFlag = "log-level"
+	PortFlag     = "port"
+
+	TokenSigningSecretFlag = "token-signing-secret"
+	HomeDirectoryFlag      = "home-directory"
+	ShutdownTimeoutFlag    = "shutdown-timeout"
+
+	DatabaseBackendFlag = "database-backend"
+
+	SQLitePathFlag = "sqlite-path"
+
+	PostgreSQLURLFlag      = "postgresql-url"
+	PostgreSQLUsernameFlag = "postgresql-username"
+	PostgreSQLPasswordFlag = "postgresql-password"
+	PostgreSQLHostFlag     = "postgresql-host"
+	PostgreSQLPortFlag     = "postgresql-port"
+	PostgreSQLDatabaseFlag = "postgresql-database"
+
+	MySQLURLFlag      = "mysql-url"
+	MySQLUsernameFlag = "mysql-username"
+	MySQLPasswordFlag = "mysql-password"
+	MySQLHostFlag     = "mysql-host"
+	MySQLPortFlag     = "mysql-port"
+	MySQLDatabaseFlag = "mysql-database"
+	MySQLTLSModeFlag  = "mysql-tls-mode"
+
+	StorageResolverFlag = "storage-resolver"
+
+	S3BucketNameFlag      = "s3-bucket-name"
+	S3BucketRegionFlag    = "s3-bucket-region"
+	S3AccessKeyIDFlag     = "s3-access-key-id"
+	S3SecretAccessKeyFlag = "s3-secret-access-key"
+	S3PresignExpireFlag   = "s3-presign-expire"
+
+	AzureAccountNameFlag   = "azure-account-name"
+	AzureAccountKeyFlag    = "azure-account-key"
+	AzureSASTokenFlag      = "azure-sas-token"
+	AzureContainerNameFlag = "azure-container-name"
+	AzurePresignExpireFlag = "azure-presign-expire"
+
+	GCSBucketNameFlag      = "gcs-bucket-name"
+	GCSCredentialsFileFlag = "gcs-credentials-file"
+	GCSPresignExpireFlag   = "gcs-presign-expire"
+
+	OAuthProviderFlag = "oauth-provider"
+
+	GitHubClientIDFlag     = "github-client-id"
+	GitHubClientSecretFlag = "github-client-secret"
+	GitHubOrganizationFlag = "github-organization"
+
+	GitLabClientIDFlag     = "gitlab-client-id"
+	GitLabClientSecretFlag = "gitlab-client-secret"
+	GitLabBaseURLFlag      = "gitlab-base-url"
+	GitLabGroupFlag        = "gitlab-group"
+
+	BitbucketClientIDFlag     = "bitbucket-client-id"
+	BitbucketClientSecretFlag = "bitbucket-client-secret"
+	BitbucketWorkspaceFlag    = "bitbucket-workspace"
+
+	GoogleClientIDFlag     = "google-client-id"
+	GoogleClientSecretFlag = "google-client-secret"
+	GoogleDomainFlag       = "google-domain"
+
+	OIDCClientIDFlag      = "oidc-client-id"
+	OIDCClientSecretFlag  = "oidc-client-secret"
+	OIDCIssuerURLFlag     = "oidc-issuer-url"
+	OIDCJWKSURLFlag       = "oidc-jwks-url"
+	OIDCGroupsClaimFlag   = "oidc-groups-claim"
+	OIDCAllowedGroupsFlag = "oidc-allowed-groups"
+
+	VaultAddressFlag   = "vault-address"
+	VaultTokenFlag     = "vault-token"
+	VaultRoleIDFlag    = "vault-role-id"
+	VaultSecretIDFlag  = "vault-secret-id"
+	VaultNamespaceFlag = "vault-namespace"
+
+	AWSSecretsManagerRegionFlag = "aws-secrets-manager-region"
+
+	TelemetryMetricsEnabledFlag = "telemetry-metrics-enabled"
+	TelemetryOTLPEndpointFlag   = "telemetry-otlp-endpoint"
+	TelemetryServiceNameFlag    = "telemetry-service-name"
+	TelemetrySampleRatioFlag    = "telemetry-sample-ratio"
+)
+
+// flags holds every flag accepted by the server command
+var flags = map[string]cli.Flag{
+	ConfigFlag: &cli.StringFlag{
+		Usage: "Path to a config file",
+	},
+	LogLevelFlag: &cli.StringFlag{
+		Usage:        "The verbosity of the logs",
+		DefaultValue: "info",
+	},
+	PortFlag: &cli.IntFlag{
+		Usage:        "The port the server will listen on",
+		DefaultValue: 5758,
+	},
+
+	TokenSigningSecretFlag: &cli.StringFlag{
+		Usage:    "The secret used to sign authentication tokens",
+		Required: true,
+	},
+	HomeDirectoryFlag: &cli.StringFlag{
+		Usage:        "The directory Terralist will use to store local data",
+		DefaultValue: "~/.terralist.d",
+	},
+	ShutdownTimeoutFlag: &cli.IntFlag{
+		Usage:        "How long, in seconds, to wait for in-flight requests to drain on shutdown",
+		DefaultValue: 30,
+	},
+
+	DatabaseBackendFlag: &cli.StringFlag{
+		Usage:        "The database backend to use (sqlite, postgresql, mysql)",
+		DefaultValue: "sqlite",
+	},
+
+	SQLitePathFlag: &cli.StringFlag{
+		Usage:        "The path to the SQLite database file",
+		DefaultValue: "terralist.db",
+	},
+
+	PostgreSQLURLFlag:      &cli.StringFlag{Usage: "The PostgreSQL connection URL"},
+	PostgreSQLUsernameFlag: &cli.StringFlag{Usage: "The PostgreSQL username"},
+	PostgreSQLPasswordFlag: &cli.StringFlag{Usage: "The PostgreSQL password"},
+	PostgreSQLHostFlag:     &cli.StringFlag{Usage: "The PostgreSQL hostname"},
+	PostgreSQLPortFlag:     &cli.IntFlag{Usage: "The PostgreSQL port", DefaultValue: 5432},
+	PostgreSQLDatabaseFlag: &cli.StringFlag{Usage: "The PostgreSQL database name"},
+
+	MySQLURLFlag:      &cli.StringFlag{Usage: "The MySQL connection URL"},
+	MySQLUsernameFlag: &cli.StringFlag{Usage: "The MySQL username"},
+	MySQLPasswordFlag: &cli.StringFlag{Usage: "The MySQL password"},
+	MySQLHostFlag:     &cli.StringFlag{Usage: "The MySQL hostname"},
+	MySQLPortFlag:     &cli.IntFlag{Usage: "The MySQL port", DefaultValue: 3306},
+	MySQLDatabaseFlag: &cli.StringFlag{Usage: "The MySQL database name"},
+	MySQLTLSModeFlag:  &cli.StringFlag{Usage: "The MySQL TLS mode (disabled, preferred, required, skip-verify)", DefaultValue: "preferred"},
+
+	StorageResolverFlag: &cli.StringFlag{
+		Usage:        "The storage resolver to use (proxy, local, s3)",
+		DefaultValue: "proxy",
+	},
+
+	S3BucketNameFlag:      &cli.StringFlag{Usage: "The S3 bucket name"},
+	S3BucketRegionFlag:    &cli.StringFlag{Usage: "The S3 bucket region"},
+	S3AccessKeyIDFlag:     &cli.StringFlag{Usage: "The S3 access key ID"},
+	S3SecretAccessKeyFlag: &cli.StringFlag{Usage: "The S3 secret access key"},
+	S3PresignExpireFlag:   &cli.IntFlag{Usage: "The expiry, in seconds, of S3 presigned URLs", DefaultValue: 900},
+
+	AzureAccountNameFlag:   &cli.StringFlag{Usage: "The Azure Blob Storage account name"},
+	AzureAccountKeyFlag:    &cli.StringFlag{Usage: "The Azure Blob Storage account key"},
+	AzureSASTokenFlag:      &cli.StringFlag{Usage: "A SAS token, used instead of the account key"},
+	AzureContainerNameFlag: &cli.StringFlag{Usage: "The Azure Blob Storage container name"},
+	AzurePresignExpireFlag: &cli.IntFlag{Usage: "The expiry, in seconds, of Azure presigned URLs", DefaultValue: 900},
+
+	GCSBucketNameFlag:      &cli.StringFlag{Usage: "The Google Cloud Storage bucket name"},
+	GCSCredentialsFileFlag: &cli.StringFlag{Usage: "Path to a service account JSON key file, required to sign URLs"},
+	GCSPresignExpireFlag:   &cli.IntFlag{Usage: "The expiry, in seconds, of GCS signed URLs", DefaultValue: 900},
+
+	OAuthProviderFlag: &cli.StringFlag{
+		Usage:        "The OAuth provider to use (github, gitlab, bitbucket, google, oidc)",
+		DefaultValue: "github",
+	},
+
+	GitHubClientIDFlag:     &cli.StringFlag{Usage: "The GitHub OAuth client ID"},
+	GitHubClientSecretFlag: &cli.StringFlag{Usage: "The GitHub OAuth client secret"},
+	GitHubOrganizationFlag: &cli.StringFlag{Usage: "Restrict login to members of this GitHub organization"},
+
+	GitLabClientIDFlag:     &cli.StringFlag{Usage: "The GitLab OAuth client ID"},
+	GitLabClientSecretFlag: &cli.StringFlag{Usage: "The GitLab OAuth client secret"},
+	GitLabBaseURLFlag:      &cli.StringFlag{Usage: "The base URL of the GitLab instance", DefaultValue: "https://gitlab.com"},
+	GitLabGroupFlag:        &cli.StringFlag{Usage: "Restrict login to members of this GitLab group"},
+
+	BitbucketClientIDFlag:     &cli.StringFlag{Usage: "The Bitbucket OAuth client ID"},
+	BitbucketClientSecretFlag: &cli.StringFlag{Usage: "The Bitbucket OAuth client secret"},
+	BitbucketWorkspaceFlag:    &cli.StringFlag{Usage: "Restrict login to members of this Bitbucket workspace"},
+
+	GoogleClientIDFlag:     &cli.StringFlag{Usage: "The Google OAuth client ID"},
+	GoogleClientSecretFlag: &cli.StringFlag{Usage: "The Google OAuth client secret"},
+	GoogleDomainFlag:       &cli.StringFlag{Usage: "Restrict login to users of this Google Workspace domain"},
+
+	OIDCClientIDFlag:      &cli.StringFlag{Usage: "The OIDC client ID"},
+	OIDCClientSecretFlag:  &cli.StringFlag{Usage: "The OIDC client secret"},
+	OIDCIssuerURLFlag:     &cli.StringFlag{Usage: "The OIDC issuer URL"},
+	OIDCJWKSURLFlag:       &cli.StringFlag{Usage: "Overrides the OIDC JWKS endpoint"},
+	OIDCGroupsClaimFlag:   &cli.StringFlag{Usage: "The ID token claim holding group/organization membership", DefaultValue: "groups"},
+	OIDCAllowedGroupsFlag: &cli.StringFlag{Usage: "Comma-separated list of groups allowed to log in"},
+
+	VaultAddressFlag:   &cli.StringFlag{Usage: "The Vault server address"},
+	VaultTokenFlag:     &cli.StringFlag{Usage: "A Vault token; leave empty to use AppRole auth"},
+	VaultRoleIDFlag:    &cli.StringFlag{Usage: "The Vault AppRole role id"},
+	VaultSecretIDFlag:  &cli.StringFlag{Usage: "The Vault AppRole secret id"},
+	VaultNamespaceFlag: &cli.StringFlag{Usage: "The Vault Enterprise namespace, if any"},
+
+	AWSSecretsManagerRegionFlag: &cli.StringFlag{Usage: "The AWS region holding secrets referenced via awssm://"},
+
+	TelemetryMetricsEnabledFlag: &cli.BoolFlag{Usage: "Mount a Prometheus /metrics endpoint"},
+	TelemetryOTLPEndpointFlag:   &cli.StringFlag{Usage: "The OTLP collector endpoint traces are exported to; tracing is disabled when empty"},
+	TelemetryServiceNameFlag:    &cli.StringFlag{Usage: "The service name reported in metrics and traces", DefaultValue: "terralist"},
+	TelemetrySampleRatioFlag:    &cli.FloatFlag{Usage: "The fraction, between 0 and 1, of traces to sample", DefaultValue: 1.0},
+}
@@ -1,25 +1,40 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"terralist/internal/server"
 	"terralist/pkg/auth"
+	"terralist/pkg/auth/bitbucket"
 	authFactory "terralist/pkg/auth/factory"
 	"terralist/pkg/auth/github"
+	"terralist/pkg/auth/gitlab"
+	"terralist/pkg/auth/google"
+	"terralist/pkg/auth/oidc"
 	"terralist/pkg/cli"
 	"terralist/pkg/database"
 	dbFactory "terralist/pkg/database/factory"
+	"terralist/pkg/database/mysql"
 	"terralist/pkg/database/postgresql"
 	"terralist/pkg/database/sqlite"
+	"terralist/pkg/secrets"
+	"terralist/pkg/secrets/awssm"
+	"terralist/pkg/secrets/vault"
 	"terralist/pkg/storage/resolver"
+	"terralist/pkg/storage/resolver/azure"
 	storageFactory "terralist/pkg/storage/resolver/factory"
+	"terralist/pkg/storage/resolver/gcs"
 	"terralist/pkg/storage/resolver/local"
 	"terralist/pkg/storage/resolver/proxy"
 	"terralist/pkg/storage/resolver/s3"
+	"terralist/pkg/telemetry"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
@@ -46,9 +61,10 @@ type Creator interface {
 // DefaultCreator is the concrete implementation of Creator
 type DefaultCreator struct{}
 
-// Starter starts the server
+// Starter starts the server and stops it once ctx is cancelled, waiting
+// for in-flight requests and storage operations to drain
 type Starter interface {
-	Start() error
+	Start(ctx context.Context) error
 }
 
 // NewServer returns the real server object
@@ -93,6 +109,8 @@ func (s *Command) Init() *cobra.Command {
 			c.Flags().Int(name, fg.DefaultValue, usage)
 		} else if fg, ok := f.(*cli.BoolFlag); ok {
 			c.Flags().Bool(name, fg.DefaultValue, usage)
+		} else if fg, ok := f.(*cli.FloatFlag); ok {
+			c.Flags().Float64(name, fg.DefaultValue, usage)
 		}
 
 		if f.IsHidden() {
@@ -142,6 +160,13 @@ func (s *Command) run() error {
 		}
 	}
 
+	// Resolve any flag value referencing an external secret provider
+	// (e.g. vault://path/to/secret#field or awssm://arn#field) before
+	// validating, so downstream code only ever sees the plaintext value
+	if err := resolveSecrets(); err != nil {
+		return err
+	}
+
 	// Validate flag values
 	for k, v := range flags {
 		if err := v.Validate(); err != nil {
@@ -189,6 +214,16 @@ func (s *Command) run() error {
 			Port:     flags[PostgreSQLPortFlag].(*cli.IntFlag).Value,
 			Name:     flags[PostgreSQLDatabaseFlag].(*cli.StringFlag).Value,
 		})
+	case "mysql":
+		db, err = dbFactory.NewDatabase(database.MYSQL, &mysql.Config{
+			URL:      flags[MySQLURLFlag].(*cli.StringFlag).Value,
+			Username: flags[MySQLUsernameFlag].(*cli.StringFlag).Value,
+			Password: flags[MySQLPasswordFlag].(*cli.StringFlag).Value,
+			Hostname: flags[MySQLHostFlag].(*cli.StringFlag).Value,
+			Port:     flags[MySQLPortFlag].(*cli.IntFlag).Value,
+			Name:     flags[MySQLDatabaseFlag].(*cli.StringFlag).Value,
+			TLSMode:  flags[MySQLTLSModeFlag].(*cli.StringFlag).Value,
+		})
 	}
 	if err != nil {
 		return err
@@ -203,6 +238,39 @@ func (s *Command) run() error {
 			ClientSecret: flags[GitHubClientSecretFlag].(*cli.StringFlag).Value,
 			Organization: flags[GitHubOrganizationFlag].(*cli.StringFlag).Value,
 		})
+	case "gitlab":
+		provider, err = authFactory.NewProvider(auth.GITLAB, &gitlab.Config{
+			ClientID:     flags[GitLabClientIDFlag].(*cli.StringFlag).Value,
+			ClientSecret: flags[GitLabClientSecretFlag].(*cli.StringFlag).Value,
+			BaseURL:      flags[GitLabBaseURLFlag].(*cli.StringFlag).Value,
+			Group:        flags[GitLabGroupFlag].(*cli.StringFlag).Value,
+		})
+	case "bitbucket":
+		provider, err = authFactory.NewProvider(auth.BITBUCKET, &bitbucket.Config{
+			ClientID:     flags[BitbucketClientIDFlag].(*cli.StringFlag).Value,
+			ClientSecret: flags[BitbucketClientSecretFlag].(*cli.StringFlag).Value,
+			Workspace:    flags[BitbucketWorkspaceFlag].(*cli.StringFlag).Value,
+		})
+	case "google":
+		provider, err = authFactory.NewProvider(auth.GOOGLE, &google.Config{
+			ClientID:     flags[GoogleClientIDFlag].(*cli.StringFlag).Value,
+			ClientSecret: flags[GoogleClientSecretFlag].(*cli.StringFlag).Value,
+			Domain:       flags[GoogleDomainFlag].(*cli.StringFlag).Value,
+		})
+	case "oidc":
+		var allowedGroups []string
+		if raw := flags[OIDCAllowedGroupsFlag].(*cli.StringFlag).Value; raw != "" {
+			allowedGroups = strings.Split(raw, ",")
+		}
+
+		provider, err = authFactory.NewProvider(auth.OIDC, &oidc.Config{
+			ClientID:      flags[OIDCClientIDFlag].(*cli.StringFlag).Value,
+			ClientSecret:  flags[OIDCClientSecretFlag].(*cli.StringFlag).Value,
+			IssuerURL:     flags[OIDCIssuerURLFlag].(*cli.StringFlag).Value,
+			JWKSURL:       flags[OIDCJWKSURLFlag].(*cli.StringFlag).Value,
+			GroupsClaim:   flags[OIDCGroupsClaimFlag].(*cli.StringFlag).Value,
+			AllowedGroups: allowedGroups,
+		})
 	}
 	if err != nil {
 		return err
@@ -243,23 +311,172 @@ func (s *Command) run() error {
 			SecretAccessKey: flags[S3SecretAccessKeyFlag].(*cli.StringFlag).Value,
 			LinkExpire:      flags[S3PresignExpireFlag].(*cli.IntFlag).Value,
 		})
+	case "azure":
+		res, err = storageFactory.NewResolver(resolver.AZURE, &azure.Config{
+			HomeDirectory: homeDir,
+			AccountName:   flags[AzureAccountNameFlag].(*cli.StringFlag).Value,
+			AccountKey:    flags[AzureAccountKeyFlag].(*cli.StringFlag).Value,
+			SASToken:      flags[AzureSASTokenFlag].(*cli.StringFlag).Value,
+			ContainerName: flags[AzureContainerNameFlag].(*cli.StringFlag).Value,
+			PresignExpire: flags[AzurePresignExpireFlag].(*cli.IntFlag).Value,
+		})
+	case "gcs":
+		res, err = storageFactory.NewResolver(resolver.GCS, &gcs.Config{
+			HomeDirectory:   homeDir,
+			BucketName:      flags[GCSBucketNameFlag].(*cli.StringFlag).Value,
+			CredentialsFile: flags[GCSCredentialsFileFlag].(*cli.StringFlag).Value,
+			PresignExpire:   flags[GCSPresignExpireFlag].(*cli.IntFlag).Value,
+		})
 	}
 	if err != nil {
 		return err
 	}
 
+	// Initialize telemetry
+	tel, err := telemetry.New(&telemetry.Config{
+		MetricsEnabled: flags[TelemetryMetricsEnabledFlag].(*cli.BoolFlag).Value,
+		OTLPEndpoint:   flags[TelemetryOTLPEndpointFlag].(*cli.StringFlag).Value,
+		ServiceName:    flags[TelemetryServiceNameFlag].(*cli.StringFlag).Value,
+		SampleRatio:    flags[TelemetrySampleRatioFlag].(*cli.FloatFlag).Value,
+	})
+	if err != nil {
+		return errors.Wrap(err, "initializing telemetry")
+	}
+
+	shutdownTimeout := time.Duration(flags[ShutdownTimeoutFlag].(*cli.IntFlag).Value) * time.Second
+
 	srv, err := s.ServerCreator.NewServer(userConfig, server.Config{
-		Database:    db,
-		Provider:    provider,
-		Resolver:    res,
-		RunningMode: s.RunningMode,
+		Database:        db,
+		Provider:        provider,
+		Resolver:        res,
+		RunningMode:     s.RunningMode,
+		ShutdownTimeout: shutdownTimeout,
+		Telemetry:       tel,
 	})
 
 	if err != nil {
 		return errors.Wrap(err, "initializing server")
 	}
 
-	return srv.Start()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	go func() {
+		for sig := range sigCh {
+			if sig == syscall.SIGHUP {
+				// This only re-reads the config file into viper; the
+				// database engine, auth provider and storage resolver
+				// above were already built from the old values and are
+				// not rebuilt. Rotating their credentials still requires
+				// a restart
+				if err := s.Viper.ReadInConfig(); err != nil {
+					log.Error().AnErr("error", err).Msg("could not reload config on SIGHUP")
+				} else {
+					log.Warn().Msg("re-read config file on SIGHUP; restart the server to apply any changed database, auth provider or storage resolver settings")
+				}
+
+				continue
+			}
+
+			cancel()
+
+			return
+		}
+	}()
+
+	return s.startAndWait(srv, ctx, shutdownTimeout)
+}
+
+// startAndWait runs srv.Start(ctx) and, once ctx is cancelled, enforces
+// shutdownTimeout as a hard bound on how long it may take to return. This
+// guarantees the process exits even if Start ignores cancellation or gets
+// stuck draining in-flight requests
+func (s *Command) startAndWait(srv Starter, ctx context.Context, shutdownTimeout time.Duration) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Start(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(shutdownTimeout):
+		return fmt.Errorf("server did not shut down within %s", shutdownTimeout)
+	}
+}
+
+// resolveSecrets scans every string flag for a value referencing an
+// external secret provider and replaces it with the resolved plaintext
+// value
+func resolveSecrets() error {
+	var providers *secrets.Providers
+
+	for k, f := range flags {
+		sf, ok := f.(*cli.StringFlag)
+		if !ok || !secrets.HasScheme(sf.Value) {
+			continue
+		}
+
+		if providers == nil {
+			p, err := newSecretProviders()
+			if err != nil {
+				return err
+			}
+
+			providers = p
+		}
+
+		resolved, err := providers.Resolve(sf.Value)
+		if err != nil {
+			return fmt.Errorf("could not resolve secret for %s: %v", k, err)
+		}
+
+		sf.Value = resolved
+	}
+
+	return nil
+}
+
+// newSecretProviders builds the set of secret providers configured via
+// flags. A provider is only initialized if its configuration was set
+func newSecretProviders() (*secrets.Providers, error) {
+	p := &secrets.Providers{}
+
+	if addr := flags[VaultAddressFlag].(*cli.StringFlag).Value; addr != "" {
+		v, err := vault.New(&vault.Config{
+			Address:   addr,
+			Token:     flags[VaultTokenFlag].(*cli.StringFlag).Value,
+			RoleID:    flags[VaultRoleIDFlag].(*cli.StringFlag).Value,
+			SecretID:  flags[VaultSecretIDFlag].(*cli.StringFlag).Value,
+			Namespace: flags[VaultNamespaceFlag].(*cli.StringFlag).Value,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing vault secret provider")
+		}
+
+		p.Vault = v
+	}
+
+	if region := flags[AWSSecretsManagerRegionFlag].(*cli.StringFlag).Value; region != "" {
+		a, err := awssm.New(&awssm.Config{Region: region})
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing aws secrets manager provider")
+		}
+
+		p.AWSSM = a
+	}
+
+	return p, nil
 }
 
 // withErrPrint prints out any cmd errors to stderr
@@ -276,4 +493,4 @@ func (s *Command) withErrPrint(f func(*cobra.Command, []string) error) func(*cob
 // printErr prints err to stderr using a red terminal color
 func (s *Command) printErr(err error) {
 	log.Error().AnErr("error", err).Send()
-}
\ No newline at end of file
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeStarter struct {
+	start func(ctx context.Context) error
+}
+
+func (f *fakeStarter) Start(ctx context.Context) error {
+	return f.start(ctx)
+}
+
+func TestStartAndWait_ReturnsStartError(t *testing.T) {
+	s := &Command{}
+	wantErr := errors.New("boom")
+
+	srv := &fakeStarter{start: func(ctx context.Context) error {
+		return wantErr
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := s.startAndWait(srv, ctx, time.Second); err != wantErr {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestStartAndWait_ReturnsNilOnCleanShutdown(t *testing.T) {
+	s := &Command{}
+
+	srv := &fakeStarter{start: func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	if err := s.startAndWait(srv, ctx, time.Second); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestStartAndWait_TimesOutIfStartNeverReturns(t *testing.T) {
+	s := &Command{}
+
+	srv := &fakeStarter{start: func(ctx context.Context) error {
+		<-ctx.Done()
+
+		select {}
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	err := s.startAndWait(srv, ctx, 50*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+}
@@ -0,0 +1,204 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"terralist/pkg/auth"
+)
+
+const (
+	tokenURL = "https://bitbucket.org/site/oauth2/access_token"
+	userURL  = "https://api.bitbucket.org/2.0/user"
+)
+
+// Config holds the configuration for the Bitbucket OAuth provider
+type Config struct {
+	ClientID     string
+	ClientSecret string
+
+	// Workspace restricts login to members of this Bitbucket workspace
+	Workspace string
+}
+
+// Provider implements auth.Provider for Bitbucket
+type Provider struct {
+	config *Config
+
+	// apiBaseURL defaults to https://api.bitbucket.org; overridable in tests
+	apiBaseURL string
+
+	httpClient *http.Client
+}
+
+// New creates a new Bitbucket auth provider
+func New(config *Config) (*Provider, error) {
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return nil, fmt.Errorf("bitbucket: client id and client secret are required")
+	}
+
+	return &Provider{
+		config:     config,
+		apiBaseURL: "https://api.bitbucket.org",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return "Bitbucket"
+}
+
+func (p *Provider) GetAuthorizeURL(state string) string {
+	return fmt.Sprintf(
+		"https://bitbucket.org/site/oauth2/authorize?client_id=%s&state=%s",
+		p.config.ClientID,
+		state,
+	)
+}
+
+func (p *Provider) GetUserDetails(code string) (*auth.User, error) {
+	token, err := p.exchangeCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("bitbucket: could not exchange code: %v", err)
+	}
+
+	var profile struct {
+		Username    string `json:"username"`
+		DisplayName string `json:"display_name"`
+	}
+
+	if err := p.getJSON(userURL, token, &profile); err != nil {
+		return nil, fmt.Errorf("bitbucket: could not fetch user: %v", err)
+	}
+
+	var email struct {
+		Values []struct {
+			Email     string `json:"email"`
+			IsPrimary bool   `json:"is_primary"`
+		} `json:"values"`
+	}
+
+	_ = p.getJSON(userURL+"/emails", token, &email)
+
+	var primaryEmail string
+	for _, e := range email.Values {
+		if e.IsPrimary {
+			primaryEmail = e.Email
+			break
+		}
+	}
+
+	return &auth.User{
+		Name:  profile.Username,
+		Email: primaryEmail,
+		Token: token,
+	}, nil
+}
+
+func (p *Provider) VerifyGroup(user *auth.User) (bool, error) {
+	if p.config.Workspace == "" {
+		return true, nil
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/2.0/user/permissions/workspaces?q=%s",
+		p.apiBaseURL,
+		url.QueryEscape(fmt.Sprintf(`workspace.slug="%s"`, p.config.Workspace)),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("bitbucket: could not verify workspace membership: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("bitbucket: unexpected status %d while verifying workspace membership", resp.StatusCode)
+	}
+
+	var permissions struct {
+		Values []json.RawMessage `json:"values"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&permissions); err != nil {
+		return false, fmt.Errorf("bitbucket: could not parse workspace permissions: %v", err)
+	}
+
+	return len(permissions.Values) > 0, nil
+}
+
+// exchangeCode trades an OAuth authorization code for an access token
+// using HTTP Basic auth, as required by Bitbucket's OAuth2 endpoint
+func (p *Provider) exchangeCode(code string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(p.config.ClientID, p.config.ClientSecret)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	if body.Error != "" {
+		return "", fmt.Errorf("%s", body.Error)
+	}
+
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("no access token returned")
+	}
+
+	return body.AccessToken, nil
+}
+
+// getJSON performs an authenticated GET request and decodes the JSON
+// response body into out
+func (p *Provider) getJSON(endpoint string, token string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
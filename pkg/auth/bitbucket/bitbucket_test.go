@@ -0,0 +1,78 @@
+package bitbucket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"terralist/pkg/auth"
+)
+
+func TestVerifyGroup_NoWorkspaceConfigured(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "jdoe"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow when no workspace is configured")
+	}
+}
+
+func TestVerifyGroup_Member(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"values": [{"permission": "member"}]}`))
+	}))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, "my-workspace")
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "jdoe", Token: "tok"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow a member of the workspace")
+	}
+}
+
+func TestVerifyGroup_NotMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"values": []}`))
+	}))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, "my-workspace")
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "jdoe", Token: "tok"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a non-member of the workspace")
+	}
+}
+
+// newTestProvider builds a Provider whose membership checks target a
+// local httptest server instead of api.bitbucket.org
+func newTestProvider(t *testing.T, apiBaseURL string, workspace string) *Provider {
+	t.Helper()
+
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", Workspace: workspace})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	p.apiBaseURL = apiBaseURL
+
+	return p
+}
@@ -0,0 +1,55 @@
+package factory
+
+import (
+	"fmt"
+
+	"terralist/pkg/auth"
+	"terralist/pkg/auth/bitbucket"
+	"terralist/pkg/auth/github"
+	"terralist/pkg/auth/gitlab"
+	"terralist/pkg/auth/google"
+	"terralist/pkg/auth/oidc"
+)
+
+// NewProvider creates a new auth.Provider based on the given provider type
+func NewProvider(providerType auth.ProviderType, config any) (auth.Provider, error) {
+	switch providerType {
+	case auth.GITHUB:
+		cfg, ok := config.(*github.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected github.Config, got %T", config)
+		}
+
+		return github.New(cfg)
+	case auth.GITLAB:
+		cfg, ok := config.(*gitlab.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected gitlab.Config, got %T", config)
+		}
+
+		return gitlab.New(cfg)
+	case auth.BITBUCKET:
+		cfg, ok := config.(*bitbucket.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected bitbucket.Config, got %T", config)
+		}
+
+		return bitbucket.New(cfg)
+	case auth.GOOGLE:
+		cfg, ok := config.(*google.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected google.Config, got %T", config)
+		}
+
+		return google.New(cfg)
+	case auth.OIDC:
+		cfg, ok := config.(*oidc.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected oidc.Config, got %T", config)
+		}
+
+		return oidc.New(cfg)
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", providerType)
+	}
+}
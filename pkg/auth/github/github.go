@@ -0,0 +1,192 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"terralist/pkg/auth"
+)
+
+const tokenURL = "https://github.com/login/oauth/access_token"
+
+// Config holds the configuration for the GitHub OAuth provider
+type Config struct {
+	ClientID     string
+	ClientSecret string
+
+	// Organization restricts login to members of this GitHub organization
+	Organization string
+}
+
+// Provider implements auth.Provider for GitHub
+type Provider struct {
+	config *Config
+
+	// apiBaseURL defaults to https://api.github.com; overridable in tests
+	apiBaseURL string
+
+	httpClient *http.Client
+}
+
+// New creates a new GitHub auth provider
+func New(config *Config) (*Provider, error) {
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return nil, fmt.Errorf("github: client id and client secret are required")
+	}
+
+	return &Provider{
+		config:     config,
+		apiBaseURL: "https://api.github.com",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return "GitHub"
+}
+
+func (p *Provider) GetAuthorizeURL(state string) string {
+	return fmt.Sprintf(
+		"https://github.com/login/oauth/authorize?client_id=%s&state=%s",
+		p.config.ClientID,
+		state,
+	)
+}
+
+func (p *Provider) GetUserDetails(code string) (*auth.User, error) {
+	token, err := p.exchangeCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("github: could not exchange code: %v", err)
+	}
+
+	var profile struct {
+		Login string `json:"login"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	if err := p.getJSON(p.apiBaseURL+"/user", token, &profile); err != nil {
+		return nil, fmt.Errorf("github: could not fetch user: %v", err)
+	}
+
+	name := profile.Name
+	if name == "" {
+		name = profile.Login
+	}
+
+	return &auth.User{
+		Name:  name,
+		Email: profile.Email,
+		Token: token,
+		Extra: map[string]string{"login": profile.Login},
+	}, nil
+}
+
+func (p *Provider) VerifyGroup(user *auth.User) (bool, error) {
+	if p.config.Organization == "" {
+		return true, nil
+	}
+
+	login := user.Extra["login"]
+	if login == "" {
+		login = user.Name
+	}
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		fmt.Sprintf("%s/orgs/%s/members/%s", p.apiBaseURL, p.config.Organization, login),
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("github: could not verify organization membership: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// A 204 response means the user is a public or private member of the
+	// organization; a 404 means they are not
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("github: unexpected status %d while verifying organization membership", resp.StatusCode)
+	}
+}
+
+// exchangeCode trades an OAuth authorization code for an access token
+func (p *Provider) exchangeCode(code string) (string, error) {
+	form := url.Values{}
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code", code)
+
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	if body.Error != "" {
+		return "", fmt.Errorf("%s", body.Error)
+	}
+
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("no access token returned")
+	}
+
+	return body.AccessToken, nil
+}
+
+// getJSON performs an authenticated GET request and decodes the JSON
+// response body into out
+func (p *Provider) getJSON(endpoint string, token string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"terralist/pkg/auth"
+)
+
+func TestVerifyGroup_NoOrganizationConfigured(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "octocat"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow when no organization is configured")
+	}
+}
+
+func TestVerifyGroup_Member(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/orgs/my-org/members/octocat") {
+			t.Fatalf("unexpected request path %s", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, "my-org")
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "octocat", Token: "tok"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow a member of the organization")
+	}
+}
+
+func TestVerifyGroup_UsesLoginNotDisplayName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/orgs/my-org/members/octocat") {
+			t.Fatalf("unexpected request path %s, want the login rather than the display name", r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, "my-org")
+
+	// Name holds the display name returned by GetUserDetails; Extra["login"]
+	// holds the GitHub login, which is what the membership API expects
+	user := &auth.User{
+		Name:  "The Octocat",
+		Token: "tok",
+		Extra: map[string]string{"login": "octocat"},
+	}
+
+	ok, err := p.VerifyGroup(user)
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow a member of the organization")
+	}
+}
+
+func TestVerifyGroup_NotMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := newTestProvider(t, server.URL, "my-org")
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "octocat", Token: "tok"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a non-member of the organization")
+	}
+}
+
+// newTestProvider builds a Provider whose membership checks target a
+// local httptest server instead of api.github.com
+func newTestProvider(t *testing.T, apiBaseURL string, organization string) *Provider {
+	t.Helper()
+
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", Organization: organization})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	p.apiBaseURL = apiBaseURL
+
+	return p
+}
@@ -0,0 +1,185 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"terralist/pkg/auth"
+)
+
+// Config holds the configuration for the GitLab OAuth provider
+type Config struct {
+	ClientID     string
+	ClientSecret string
+
+	// BaseURL allows pointing to a self-hosted GitLab instance
+	BaseURL string
+
+	// Group restricts login to members of this GitLab group
+	Group string
+}
+
+// Provider implements auth.Provider for GitLab
+type Provider struct {
+	config *Config
+
+	httpClient *http.Client
+}
+
+// New creates a new GitLab auth provider
+func New(config *Config) (*Provider, error) {
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return nil, fmt.Errorf("gitlab: client id and client secret are required")
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = "https://gitlab.com"
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+
+	return &Provider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	return "GitLab"
+}
+
+func (p *Provider) GetAuthorizeURL(state string) string {
+	return fmt.Sprintf(
+		"%s/oauth/authorize?client_id=%s&state=%s",
+		p.config.BaseURL,
+		p.config.ClientID,
+		state,
+	)
+}
+
+func (p *Provider) GetUserDetails(code string) (*auth.User, error) {
+	token, err := p.exchangeCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: could not exchange code: %v", err)
+	}
+
+	var profile struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	if err := p.getJSON(p.config.BaseURL+"/api/v4/user", token, &profile); err != nil {
+		return nil, fmt.Errorf("gitlab: could not fetch user: %v", err)
+	}
+
+	return &auth.User{
+		Name:  strconv.Itoa(profile.ID),
+		Email: profile.Email,
+		Token: token,
+	}, nil
+}
+
+func (p *Provider) VerifyGroup(user *auth.User) (bool, error) {
+	if p.config.Group == "" {
+		return true, nil
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/api/v4/groups/%s/members/all/%s",
+		p.config.BaseURL,
+		url.PathEscape(p.config.Group),
+		user.Name,
+	)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+user.Token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("gitlab: could not verify group membership: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("gitlab: unexpected status %d while verifying group membership", resp.StatusCode)
+	}
+}
+
+// exchangeCode trades an OAuth authorization code for an access token
+func (p *Provider) exchangeCode(code string) (string, error) {
+	form := url.Values{}
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code", code)
+	form.Set("grant_type", "authorization_code")
+
+	req, err := http.NewRequest(http.MethodPost, p.config.BaseURL+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	if body.Error != "" {
+		return "", fmt.Errorf("%s", body.Error)
+	}
+
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("no access token returned")
+	}
+
+	return body.AccessToken, nil
+}
+
+// getJSON performs an authenticated GET request and decodes the JSON
+// response body into out
+func (p *Provider) getJSON(endpoint string, token string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
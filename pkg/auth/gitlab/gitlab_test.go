@@ -0,0 +1,67 @@
+package gitlab
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"terralist/pkg/auth"
+)
+
+func TestVerifyGroup_NoGroupConfigured(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "42"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow when no group is configured")
+	}
+}
+
+func TestVerifyGroup_Member(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", BaseURL: server.URL, Group: "my-group"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "42", Token: "tok"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow a member of the group")
+	}
+}
+
+func TestVerifyGroup_NotMember(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", BaseURL: server.URL, Group: "my-group"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "42", Token: "tok"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a non-member of the group")
+	}
+}
@@ -0,0 +1,79 @@
+package google
+
+import (
+	"testing"
+
+	"terralist/pkg/auth"
+)
+
+func TestVerifyGroup_NoDomainConfigured(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{Name: "jdoe"})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow when no domain is configured")
+	}
+}
+
+func TestVerifyGroup_MatchingDomain(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", Domain: "acme.com"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	user := &auth.User{Name: "jdoe", Extra: map[string]string{"hosted_domain": "acme.com"}}
+
+	ok, err := p.VerifyGroup(user)
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow a user from the configured domain")
+	}
+}
+
+func TestVerifyGroup_MissingHostedDomain(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", Domain: "acme.com"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	// A consumer account (e.g. Gmail) has no hd claim at all; the email's
+	// domain must not be used as a substitute
+	user := &auth.User{Name: "jdoe", Email: "jdoe@acme.com", Extra: map[string]string{"hosted_domain": ""}}
+
+	ok, err := p.VerifyGroup(user)
+	if err == nil {
+		t.Fatal("expected VerifyGroup() to return an error when the hosted domain is unknown")
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a user with no hosted domain, even if their email domain matches")
+	}
+}
+
+func TestVerifyGroup_MismatchedDomain(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", Domain: "acme.com"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	user := &auth.User{Name: "jdoe", Extra: map[string]string{"hosted_domain": "other.com"}}
+
+	ok, err := p.VerifyGroup(user)
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a user from a different domain")
+	}
+}
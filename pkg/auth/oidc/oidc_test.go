@@ -0,0 +1,191 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"terralist/pkg/auth"
+)
+
+// newTestIssuer spins up an httptest server that serves a well-known OIDC
+// discovery document and a JWKS containing pub's public key under kid
+func newTestIssuer(t *testing.T, pub *rsa.PublicKey, kid string) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{
+					"kty": "RSA",
+					"kid": kid,
+					"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// signRS256 builds a JWT signed with key under kid
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	if err != nil {
+		t.Fatalf("could not marshal header: %v", err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("could not marshal claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("could not sign token: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifyGroup_NoAllowedGroupsConfigured(t *testing.T) {
+	p, err := New(&Config{ClientID: "id", ClientSecret: "secret", IssuerURL: "https://issuer.example.com"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	ok, err := p.VerifyGroup(&auth.User{})
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow when no groups are configured")
+	}
+}
+
+func TestVerifyGroup_AllowedGroup(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	issuer := newTestIssuer(t, &key.PublicKey, "test-key")
+
+	p, err := New(&Config{
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		IssuerURL:     issuer.URL,
+		AllowedGroups: []string{"engineering", "sre"},
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	idToken := signRS256(t, key, "test-key", map[string]any{"groups": []any{"sre", "finance"}})
+	user := &auth.User{Extra: map[string]string{"id_token": idToken}}
+
+	ok, err := p.VerifyGroup(user)
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected VerifyGroup() to allow a user in an allowed group")
+	}
+}
+
+func TestVerifyGroup_DisallowedGroup(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate key: %v", err)
+	}
+
+	issuer := newTestIssuer(t, &key.PublicKey, "test-key")
+
+	p, err := New(&Config{
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		IssuerURL:     issuer.URL,
+		AllowedGroups: []string{"engineering", "sre"},
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	idToken := signRS256(t, key, "test-key", map[string]any{"groups": []any{"finance"}})
+	user := &auth.User{Extra: map[string]string{"id_token": idToken}}
+
+	ok, err := p.VerifyGroup(user)
+	if err != nil {
+		t.Fatalf("VerifyGroup() returned error: %v", err)
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a user not in an allowed group")
+	}
+}
+
+func TestVerifyGroup_RejectsTokenSignedByUnknownKey(t *testing.T) {
+	signingKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate signing key: %v", err)
+	}
+
+	trustedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate trusted key: %v", err)
+	}
+
+	// The issuer's JWKS only advertises trustedKey; the token is signed by
+	// a different key, as if an attacker forged a token with groups=sre
+	issuer := newTestIssuer(t, &trustedKey.PublicKey, "test-key")
+
+	p, err := New(&Config{
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		IssuerURL:     issuer.URL,
+		AllowedGroups: []string{"sre"},
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	idToken := signRS256(t, signingKey, "test-key", map[string]any{"groups": []any{"sre"}})
+	user := &auth.User{Extra: map[string]string{"id_token": idToken}}
+
+	ok, err := p.VerifyGroup(user)
+	if err == nil {
+		t.Fatal("expected VerifyGroup() to return an error for a forged signature")
+	}
+
+	if ok {
+		t.Fatal("expected VerifyGroup() to deny a token that doesn't verify against the issuer's jwks")
+	}
+}
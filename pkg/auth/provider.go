@@ -0,0 +1,45 @@
+package auth
+
+// ProviderType identifies a supported OAuth provider implementation
+type ProviderType string
+
+const (
+	GITHUB    ProviderType = "GITHUB"
+	GITLAB    ProviderType = "GITLAB"
+	BITBUCKET ProviderType = "BITBUCKET"
+	GOOGLE    ProviderType = "GOOGLE"
+	OIDC      ProviderType = "OIDC"
+)
+
+// User holds the details Terralist needs about an authenticated user
+type User struct {
+	Name  string
+	Email string
+
+	// Token is the OAuth access token issued to this user, kept around so
+	// VerifyGroup can make further authenticated API calls
+	Token string
+
+	// Extra carries provider-specific details gathered during
+	// GetUserDetails that VerifyGroup needs but that don't belong on
+	// every provider, e.g. Google's Workspace hosted domain
+	Extra map[string]string
+}
+
+// Provider is the interface implemented by every OAuth provider supported
+// by Terralist
+type Provider interface {
+	// Name returns the provider's display name
+	Name() string
+
+	// GetAuthorizeURL builds the URL the user is redirected to in order to
+	// start the OAuth flow
+	GetAuthorizeURL(state string) string
+
+	// GetUserDetails exchanges the OAuth code for the authenticated user
+	GetUserDetails(code string) (*User, error)
+
+	// VerifyGroup checks that the authenticated user belongs to the
+	// organization, group or domain configured for this provider
+	VerifyGroup(user *User) (bool, error)
+}
@@ -0,0 +1,182 @@
+package cli
+
+import "fmt"
+
+// Flag is the interface implemented by every CLI flag definition
+type Flag interface {
+	// Set parses and stores value, falling back to the default when value
+	// is nil
+	Set(value any) error
+
+	// Validate checks the current value is acceptable
+	Validate() error
+
+	// Format returns the flag's usage line
+	Format() string
+
+	// IsHidden reports whether the flag should be hidden from --help
+	IsHidden() bool
+}
+
+// StringFlag is a CLI flag whose value is a string
+type StringFlag struct {
+	Usage        string
+	DefaultValue string
+	Value        string
+	Hidden       bool
+
+	Required bool
+}
+
+func (f *StringFlag) Set(value any) error {
+	if value == nil {
+		f.Value = f.DefaultValue
+		return nil
+	}
+
+	v, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+
+	f.Value = v
+
+	return nil
+}
+
+func (f *StringFlag) Validate() error {
+	if f.Required && f.Value == "" {
+		return fmt.Errorf("a value is required")
+	}
+
+	return nil
+}
+
+func (f *StringFlag) Format() string {
+	return f.Usage
+}
+
+func (f *StringFlag) IsHidden() bool {
+	return f.Hidden
+}
+
+// IntFlag is a CLI flag whose value is an int
+type IntFlag struct {
+	Usage        string
+	DefaultValue int
+	Value        int
+	Hidden       bool
+}
+
+func (f *IntFlag) Set(value any) error {
+	if value == nil {
+		f.Value = f.DefaultValue
+		return nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		f.Value = v
+	case int64:
+		f.Value = int(v)
+	case float64:
+		f.Value = int(v)
+	default:
+		return fmt.Errorf("expected an int, got %T", value)
+	}
+
+	return nil
+}
+
+func (f *IntFlag) Validate() error {
+	return nil
+}
+
+func (f *IntFlag) Format() string {
+	return f.Usage
+}
+
+func (f *IntFlag) IsHidden() bool {
+	return f.Hidden
+}
+
+// FloatFlag is a CLI flag whose value is a float64
+type FloatFlag struct {
+	Usage        string
+	DefaultValue float64
+	Value        float64
+	Hidden       bool
+}
+
+func (f *FloatFlag) Set(value any) error {
+	if value == nil {
+		f.Value = f.DefaultValue
+		return nil
+	}
+
+	switch v := value.(type) {
+	case float64:
+		f.Value = v
+	case float32:
+		f.Value = float64(v)
+	case int:
+		f.Value = float64(v)
+	default:
+		return fmt.Errorf("expected a float, got %T", value)
+	}
+
+	return nil
+}
+
+func (f *FloatFlag) Validate() error {
+	return nil
+}
+
+func (f *FloatFlag) Format() string {
+	return f.Usage
+}
+
+func (f *FloatFlag) IsHidden() bool {
+	return f.Hidden
+}
+
+// BoolFlag is a CLI flag whose value is a bool
+type BoolFlag struct {
+	Usage        string
+	DefaultValue bool
+	Value        bool
+	Hidden       bool
+}
+
+func (f *BoolFlag) Set(value any) error {
+	if value == nil {
+		f.Value = f.DefaultValue
+		return nil
+	}
+
+	v, ok := value.(bool)
+	if !ok {
+		return fmt.Errorf("expected a bool, got %T", value)
+	}
+
+	f.Value = v
+
+	return nil
+}
+
+func (f *BoolFlag) Validate() error {
+	return nil
+}
+
+func (f *BoolFlag) Format() string {
+	return f.Usage
+}
+
+func (f *BoolFlag) IsHidden() bool {
+	return f.Hidden
+}
+
+// UsageTmpl renders the usage template for a command's flags
+func UsageTmpl(flags map[string]Flag) string {
+	return "Usage:\n  {{.UseLine}}\n\nFlags:\n{{.LocalFlags.FlagUsages}}"
+}
@@ -0,0 +1,78 @@
+package cli
+
+import "testing"
+
+func TestStringFlag_SetAndValidate(t *testing.T) {
+	f := &StringFlag{DefaultValue: "info", Required: true}
+
+	if err := f.Set(nil); err != nil {
+		t.Fatalf("Set(nil) returned error: %v", err)
+	}
+
+	if f.Value != "info" {
+		t.Fatalf("expected the default value, got %q", f.Value)
+	}
+
+	if err := f.Set("debug"); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+
+	if f.Value != "debug" {
+		t.Fatalf("expected debug, got %q", f.Value)
+	}
+
+	if err := f.Set(42); err == nil {
+		t.Fatal("expected an error when setting a non-string value")
+	}
+
+	f.Value = ""
+	if err := f.Validate(); err == nil {
+		t.Fatal("expected an error when a required flag is empty")
+	}
+}
+
+func TestIntFlag_SetAcceptsAnyNumericType(t *testing.T) {
+	f := &IntFlag{DefaultValue: 8080}
+
+	if err := f.Set(nil); err != nil {
+		t.Fatalf("Set(nil) returned error: %v", err)
+	}
+	if f.Value != 8080 {
+		t.Fatalf("expected the default value, got %d", f.Value)
+	}
+
+	for _, value := range []any{9090, int64(9090), float64(9090)} {
+		if err := f.Set(value); err != nil {
+			t.Fatalf("Set(%v) returned error: %v", value, err)
+		}
+		if f.Value != 9090 {
+			t.Fatalf("expected 9090, got %d", f.Value)
+		}
+	}
+
+	if err := f.Set("not a number"); err == nil {
+		t.Fatal("expected an error when setting a non-numeric value")
+	}
+}
+
+func TestBoolFlag_Set(t *testing.T) {
+	f := &BoolFlag{DefaultValue: true}
+
+	if err := f.Set(nil); err != nil {
+		t.Fatalf("Set(nil) returned error: %v", err)
+	}
+	if f.Value != true {
+		t.Fatal("expected the default value")
+	}
+
+	if err := f.Set(false); err != nil {
+		t.Fatalf("Set() returned error: %v", err)
+	}
+	if f.Value != false {
+		t.Fatal("expected false")
+	}
+
+	if err := f.Set("true"); err == nil {
+		t.Fatal("expected an error when setting a non-bool value")
+	}
+}
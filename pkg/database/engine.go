@@ -0,0 +1,26 @@
+package database
+
+import "database/sql"
+
+// BackendType identifies a supported database backend implementation
+type BackendType string
+
+const (
+	SQLITE     BackendType = "SQLITE"
+	POSTGRESQL BackendType = "POSTGRESQL"
+	MYSQL      BackendType = "MYSQL"
+)
+
+// Engine abstracts the database backend used to persist registry data
+type Engine interface {
+	// Connect opens the underlying connection
+	Connect() error
+
+	// Close closes the underlying connection
+	Close() error
+
+	// DB returns the underlying connection, so callers that need to run
+	// raw queries against the backend, such as the migrate command,
+	// don't have to duplicate connection handling
+	DB() *sql.DB
+}
@@ -0,0 +1,39 @@
+package factory
+
+import (
+	"fmt"
+
+	"terralist/pkg/database"
+	"terralist/pkg/database/mysql"
+	"terralist/pkg/database/postgresql"
+	"terralist/pkg/database/sqlite"
+)
+
+// NewDatabase creates a new database.Engine based on the given backend type
+func NewDatabase(backendType database.BackendType, config any) (database.Engine, error) {
+	switch backendType {
+	case database.SQLITE:
+		cfg, ok := config.(*sqlite.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected sqlite.Config, got %T", config)
+		}
+
+		return sqlite.New(cfg)
+	case database.POSTGRESQL:
+		cfg, ok := config.(*postgresql.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected postgresql.Config, got %T", config)
+		}
+
+		return postgresql.New(cfg)
+	case database.MYSQL:
+		cfg, ok := config.(*mysql.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected mysql.Config, got %T", config)
+		}
+
+		return mysql.New(cfg)
+	default:
+		return nil, fmt.Errorf("unknown backend type %q", backendType)
+	}
+}
@@ -0,0 +1,101 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// Config holds the configuration for the MySQL/MariaDB backend
+type Config struct {
+	URL      string
+	Username string
+	Password string
+	Hostname string
+	Port     int
+	Name     string
+
+	// TLSMode controls the TLS mode used to connect, e.g. "disabled",
+	// "preferred", "required" or "skip-verify"
+	TLSMode string
+}
+
+// Engine implements database.Engine backed by MySQL/MariaDB
+type Engine struct {
+	config *Config
+
+	db *sql.DB
+}
+
+// New creates a new MySQL engine
+func New(config *Config) (*Engine, error) {
+	if config.URL == "" && config.Hostname == "" {
+		return nil, fmt.Errorf("mysql: either url or hostname is required")
+	}
+
+	if config.TLSMode == "" {
+		config.TLSMode = "preferred"
+	}
+
+	return &Engine{config: config}, nil
+}
+
+// dsn builds the connection string to pass to the mysql driver
+func (e *Engine) dsn() string {
+	if e.config.URL != "" {
+		return e.config.URL
+	}
+
+	port := e.config.Port
+	if port == 0 {
+		port = 3306
+	}
+
+	return fmt.Sprintf(
+		"%s:%s@tcp(%s:%d)/%s?tls=%s",
+		e.config.Username, e.config.Password, e.config.Hostname, port, e.config.Name, tlsParam(e.config.TLSMode),
+	)
+}
+
+// tlsParam maps our documented TLSMode values to the tls query parameter
+// values the go-sql-driver/mysql driver actually accepts.
+func tlsParam(mode string) string {
+	switch mode {
+	case "disabled":
+		return "false"
+	case "required":
+		return "true"
+	default:
+		// "preferred" and "skip-verify" match the driver's own vocabulary
+		return mode
+	}
+}
+
+func (e *Engine) Connect() error {
+	db, err := sql.Open("mysql", e.dsn())
+	if err != nil {
+		return fmt.Errorf("mysql: could not open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("mysql: could not connect to database: %v", err)
+	}
+
+	e.db = db
+
+	return nil
+}
+
+func (e *Engine) Close() error {
+	if e.db == nil {
+		return nil
+	}
+
+	return e.db.Close()
+}
+
+func (e *Engine) DB() *sql.DB {
+	return e.db
+}
@@ -0,0 +1,79 @@
+package postgresql
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+)
+
+// Config holds the configuration for the PostgreSQL backend
+type Config struct {
+	URL      string
+	Username string
+	Password string
+	Hostname string
+	Port     int
+	Name     string
+}
+
+// Engine implements database.Engine backed by PostgreSQL
+type Engine struct {
+	config *Config
+
+	db *sql.DB
+}
+
+// New creates a new PostgreSQL engine
+func New(config *Config) (*Engine, error) {
+	if config.URL == "" && config.Hostname == "" {
+		return nil, fmt.Errorf("postgresql: either url or hostname is required")
+	}
+
+	return &Engine{config: config}, nil
+}
+
+// dsn builds the connection string to pass to the postgres driver
+func (e *Engine) dsn() string {
+	if e.config.URL != "" {
+		return e.config.URL
+	}
+
+	port := e.config.Port
+	if port == 0 {
+		port = 5432
+	}
+
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		e.config.Hostname, port, e.config.Username, e.config.Password, e.config.Name,
+	)
+}
+
+func (e *Engine) Connect() error {
+	db, err := sql.Open("postgres", e.dsn())
+	if err != nil {
+		return fmt.Errorf("postgresql: could not open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("postgresql: could not connect to database: %v", err)
+	}
+
+	e.db = db
+
+	return nil
+}
+
+func (e *Engine) Close() error {
+	if e.db == nil {
+		return nil
+	}
+
+	return e.db.Close()
+}
+
+func (e *Engine) DB() *sql.DB {
+	return e.db
+}
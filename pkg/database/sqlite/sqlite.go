@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Config holds the configuration for the SQLite backend
+type Config struct {
+	// Path is the location of the SQLite database file
+	Path string
+}
+
+// Engine implements database.Engine backed by a SQLite file
+type Engine struct {
+	config *Config
+
+	db *sql.DB
+}
+
+// New creates a new SQLite engine
+func New(config *Config) (*Engine, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("sqlite: path is required")
+	}
+
+	return &Engine{config: config}, nil
+}
+
+func (e *Engine) Connect() error {
+	db, err := sql.Open("sqlite3", e.config.Path)
+	if err != nil {
+		return fmt.Errorf("sqlite: could not open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("sqlite: could not connect to database: %v", err)
+	}
+
+	e.db = db
+
+	return nil
+}
+
+func (e *Engine) Close() error {
+	if e.db == nil {
+		return nil
+	}
+
+	return e.db.Close()
+}
+
+func (e *Engine) DB() *sql.DB {
+	return e.db
+}
@@ -0,0 +1,146 @@
+package awssm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config holds the configuration needed to reach AWS Secrets Manager
+type Config struct {
+	// Region is the AWS region the secrets live in
+	Region string
+
+	// Endpoint overrides the default Secrets Manager endpoint, for tests
+	Endpoint string
+}
+
+// Resolver implements secrets.Resolver backed by AWS Secrets Manager
+type Resolver struct {
+	config *Config
+
+	httpClient *http.Client
+}
+
+// New creates a new AWS Secrets Manager resolver
+func New(config *Config) (*Resolver, error) {
+	if config.Region == "" {
+		return nil, fmt.Errorf("awssm: region is required")
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", config.Region)
+	}
+
+	return &Resolver{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Resolve fetches field out of the secret identified by arn, given a
+// reference shaped as "arn#field". Credentials are resolved through the
+// default AWS SDK chain
+func (r *Resolver) Resolve(ref string) (string, error) {
+	arn, field, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("awssm: invalid reference %q, expected arn#field", ref)
+	}
+
+	secret, err := r.getSecretValue(arn)
+	if err != nil {
+		return "", fmt.Errorf("awssm: could not resolve %s#%s: %v", arn, field, err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(secret), &data); err != nil {
+		return "", fmt.Errorf("awssm: could not resolve %s#%s: secret is not a JSON object", arn, field)
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("awssm: could not resolve %s#%s: field not found", arn, field)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("awssm: could not resolve %s#%s: field is not a string", arn, field)
+	}
+
+	return str, nil
+}
+
+// getSecretValue calls the Secrets Manager GetSecretValue action on arn
+// and returns its SecretString
+func (r *Resolver) getSecretValue(arn string) (string, error) {
+	creds, err := credentialsFromEnv()
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(map[string]string{"SecretId": arn})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.config.Endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds.signRequest(req, sha256Hex(string(body)), time.Now().UTC(), r.config.Region)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("secrets manager returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("could not parse secrets manager response: %v", err)
+	}
+
+	return result.SecretString, nil
+}
+
+// credentials holds the static AWS credentials used to sign requests
+type credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// credentialsFromEnv reads AWS credentials from the standard environment
+// variables, mirroring the first step of the AWS SDK's default chain
+func credentialsFromEnv() (*credentials, error) {
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+
+	return &credentials{
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package awssm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolve_SendsSignedRequestAndExtractsField(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	var gotTarget string
+	var gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTarget = r.Header.Get("X-Amz-Target")
+		gotAuth = r.Header.Get("Authorization")
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_, _ = w.Write([]byte(`{"SecretString":"{\"password\":\"hunter2\"}"}`))
+	}))
+	defer server.Close()
+
+	r, err := New(&Config{Region: "us-east-1", Endpoint: server.URL})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	value, err := r.Resolve("arn:aws:secretsmanager:us-east-1:123:secret:my-secret#password")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	if value != "hunter2" {
+		t.Fatalf("expected hunter2, got %s", value)
+	}
+
+	if gotTarget != "secretsmanager.GetSecretValue" {
+		t.Fatalf("expected the GetSecretValue target, got %s", gotTarget)
+	}
+
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 ") {
+		t.Fatalf("expected a SigV4 Authorization header, got %q", gotAuth)
+	}
+}
+
+func TestResolve_InvalidReference(t *testing.T) {
+	r, err := New(&Config{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if _, err := r.Resolve("arn-without-field"); err == nil {
+		t.Fatal("expected an error for a reference without a field")
+	}
+}
+
+func TestResolve_MissingCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	r, err := New(&Config{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if _, err := r.Resolve("arn:aws:secretsmanager:us-east-1:123:secret:my-secret#password"); err == nil {
+		t.Fatal("expected an error when no credentials are configured")
+	}
+}
@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"terralist/pkg/secrets/awssm"
+	"terralist/pkg/secrets/vault"
+)
+
+// Resolver fetches a secret value from an external secret provider
+type Resolver interface {
+	// Resolve returns the value stored at ref, a provider-specific
+	// reference with the scheme already stripped (e.g.
+	// "path/to/secret#field")
+	Resolve(ref string) (string, error)
+}
+
+// Providers holds one configured Resolver per supported scheme
+type Providers struct {
+	Vault *vault.Resolver
+	AWSSM *awssm.Resolver
+}
+
+// Resolve inspects value for a known secret reference scheme
+// (vault://... or awssm://...) and, if found, fetches and returns the
+// referenced secret. Values without a recognized scheme are returned
+// unchanged, so plaintext flag values keep working
+func (p *Providers) Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "vault://"):
+		if p.Vault == nil {
+			return "", fmt.Errorf("secrets: vault provider is not configured")
+		}
+
+		return p.Vault.Resolve(strings.TrimPrefix(value, "vault://"))
+	case strings.HasPrefix(value, "awssm://"):
+		if p.AWSSM == nil {
+			return "", fmt.Errorf("secrets: aws secrets manager provider is not configured")
+		}
+
+		return p.AWSSM.Resolve(strings.TrimPrefix(value, "awssm://"))
+	default:
+		return value, nil
+	}
+}
+
+// HasScheme reports whether value looks like a secret reference, so
+// callers can skip resolution entirely when no provider is configured
+func HasScheme(value string) bool {
+	return strings.HasPrefix(value, "vault://") || strings.HasPrefix(value, "awssm://")
+}
@@ -0,0 +1,43 @@
+package secrets
+
+import "testing"
+
+func TestResolve_ReturnsPlaintextValuesUnchanged(t *testing.T) {
+	p := &Providers{}
+
+	value, err := p.Resolve("plaintext-secret")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	if value != "plaintext-secret" {
+		t.Fatalf("expected the value to be returned unchanged, got %q", value)
+	}
+}
+
+func TestResolve_ReturnsErrorWhenProviderNotConfigured(t *testing.T) {
+	p := &Providers{}
+
+	if _, err := p.Resolve("vault://secret/app#token"); err == nil {
+		t.Fatal("expected an error when the vault provider isn't configured")
+	}
+
+	if _, err := p.Resolve("awssm://my-secret#token"); err == nil {
+		t.Fatal("expected an error when the aws secrets manager provider isn't configured")
+	}
+}
+
+func TestHasScheme(t *testing.T) {
+	cases := map[string]bool{
+		"vault://secret/app#token": true,
+		"awssm://my-secret#token":  true,
+		"plaintext-secret":         false,
+		"":                         false,
+	}
+
+	for value, want := range cases {
+		if got := HasScheme(value); got != want {
+			t.Fatalf("HasScheme(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
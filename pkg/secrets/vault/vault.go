@@ -0,0 +1,194 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config holds the configuration needed to reach a Vault KV v2 store
+type Config struct {
+	// Address is the Vault server address, e.g. https://vault.internal:8200
+	Address string
+
+	// Token authenticates directly with a Vault token. Leave empty to
+	// use AppRole authentication instead
+	Token string
+
+	// RoleID and SecretID authenticate via AppRole when Token is empty
+	RoleID   string
+	SecretID string
+
+	// Namespace is the Vault Enterprise namespace, if any
+	Namespace string
+}
+
+// Resolver implements secrets.Resolver backed by a Vault KV v2 secret
+// engine
+type Resolver struct {
+	config *Config
+
+	httpClient *http.Client
+
+	// token is the token used to authenticate requests, either the
+	// configured one or the one obtained through an AppRole login
+	token string
+}
+
+// New creates a new Vault secret resolver
+func New(config *Config) (*Resolver, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("vault: address is required")
+	}
+
+	if config.Token == "" && (config.RoleID == "" || config.SecretID == "") {
+		return nil, fmt.Errorf("vault: either a token or an approle role id/secret id pair is required")
+	}
+
+	return &Resolver{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		token:      config.Token,
+	}, nil
+}
+
+// Resolve fetches field from the KV v2 secret stored at path, given a
+// reference shaped as "path/to/secret#field"
+func (r *Resolver) Resolve(ref string) (string, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok {
+		return "", fmt.Errorf("vault: invalid reference %q, expected path#field", ref)
+	}
+
+	token, err := r.authToken()
+	if err != nil {
+		return "", fmt.Errorf("vault: could not authenticate: %v", err)
+	}
+
+	data, err := r.readSecret(token, path)
+	if err != nil {
+		return "", fmt.Errorf("vault: could not resolve %s#%s: %v", path, field, err)
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault: could not resolve %s#%s: field not found", path, field)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault: could not resolve %s#%s: field is not a string", path, field)
+	}
+
+	return str, nil
+}
+
+// authToken returns the token to authenticate requests with, logging in
+// via AppRole on demand when no static token is configured
+func (r *Resolver) authToken() (string, error) {
+	if r.token != "" {
+		return r.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"role_id":   r.config.RoleID,
+		"secret_id": r.config.SecretID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.config.Address+"/v1/auth/approle/login", strings.NewReader(string(body)))
+	if err != nil {
+		return "", err
+	}
+	r.setNamespace(req)
+
+	resp, err := r.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var login struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := json.Unmarshal(resp, &login); err != nil {
+		return "", fmt.Errorf("could not parse login response: %v", err)
+	}
+
+	if login.Auth.ClientToken == "" {
+		return "", fmt.Errorf("login response did not include a client token")
+	}
+
+	r.token = login.Auth.ClientToken
+
+	return r.token, nil
+}
+
+// readSecret reads the "data" field of a KV v2 secret stored at path
+func (r *Resolver) readSecret(token string, path string) (map[string]any, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s", r.config.Address, kvDataPath(path)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	r.setNamespace(req)
+
+	resp, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(resp, &secret); err != nil {
+		return nil, fmt.Errorf("could not parse secret response: %v", err)
+	}
+
+	return secret.Data.Data, nil
+}
+
+func (r *Resolver) setNamespace(req *http.Request) {
+	if r.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.config.Namespace)
+	}
+}
+
+// do executes req and returns the response body, failing on non-2xx status
+func (r *Resolver) do(req *http.Request) ([]byte, error) {
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return body, nil
+}
+
+// kvDataPath rewrites a KV v2 mount-relative path (e.g. "secret/app") into
+// its data API path (e.g. "secret/data/app")
+func kvDataPath(path string) string {
+	mount, rest, ok := strings.Cut(path, "/")
+	if !ok {
+		return path
+	}
+
+	return fmt.Sprintf("%s/data/%s", mount, rest)
+}
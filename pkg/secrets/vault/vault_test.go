@@ -0,0 +1,86 @@
+package vault
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolve_WithStaticToken(t *testing.T) {
+	var gotToken string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotToken = r.Header.Get("X-Vault-Token")
+
+		if r.URL.Path != "/v1/secret/data/app" {
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"data":{"password":"hunter2"}}}`))
+	}))
+	defer server.Close()
+
+	r, err := New(&Config{Address: server.URL, Token: "s.mytoken"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	value, err := r.Resolve("secret/app#password")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	if value != "hunter2" {
+		t.Fatalf("expected hunter2, got %s", value)
+	}
+
+	if gotToken != "s.mytoken" {
+		t.Fatalf("expected the configured token to be sent, got %s", gotToken)
+	}
+}
+
+func TestResolve_WithAppRoleLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"auth":{"client_token":"s.approletoken"}}`))
+		case "/v1/secret/data/app":
+			if r.Header.Get("X-Vault-Token") != "s.approletoken" {
+				t.Fatalf("expected the approle token to be sent, got %s", r.Header.Get("X-Vault-Token"))
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"hunter2"}}}`))
+		default:
+			t.Fatalf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	r, err := New(&Config{Address: server.URL, RoleID: "role", SecretID: "secret"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	value, err := r.Resolve("secret/app#password")
+	if err != nil {
+		t.Fatalf("Resolve() returned error: %v", err)
+	}
+
+	if value != "hunter2" {
+		t.Fatalf("expected hunter2, got %s", value)
+	}
+}
+
+func TestResolve_InvalidReference(t *testing.T) {
+	r, err := New(&Config{Address: "https://vault.example.com", Token: "token"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if _, err := r.Resolve("secret-without-field"); err == nil {
+		t.Fatal("expected an error for a reference without a field")
+	}
+}
@@ -0,0 +1,373 @@
+package azure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the configuration for the Azure Blob Storage resolver
+type Config struct {
+	HomeDirectory string
+
+	AccountName   string
+	AccountKey    string
+	SASToken      string
+	ContainerName string
+
+	// PresignExpire is how long, in seconds, presigned download URLs
+	// remain valid
+	PresignExpire int
+
+	// Endpoint overrides the default Azure Blob Storage endpoint, for
+	// Azurite or other compatible emulators
+	Endpoint string
+}
+
+// Resolver implements resolver.Resolver by storing archives in an Azure
+// Blob Storage container and serving them through presigned, time-limited
+// SAS URLs
+type Resolver struct {
+	config *Config
+
+	httpClient *http.Client
+}
+
+// New creates a new Azure Blob Storage resolver
+func New(config *Config) (*Resolver, error) {
+	if config.AccountName == "" {
+		return nil, fmt.Errorf("azure: account name is required")
+	}
+
+	if config.ContainerName == "" {
+		return nil, fmt.Errorf("azure: container name is required")
+	}
+
+	if config.AccountKey == "" && config.SASToken == "" {
+		return nil, fmt.Errorf("azure: either account key or sas token is required")
+	}
+
+	if config.PresignExpire <= 0 {
+		config.PresignExpire = 900
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+
+	return &Resolver{
+		config:     config,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// blobURL returns the URL of key within the configured container
+func (r *Resolver) blobURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", r.config.Endpoint, r.config.ContainerName, key)
+}
+
+func (r *Resolver) Store(key string, content []byte) error {
+	req, err := http.NewRequest(http.MethodPut, r.blobURL(key), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("Content-Length", strconv.Itoa(len(content)))
+
+	if err := r.authorize(req); err != nil {
+		return fmt.Errorf("azure: could not sign request: %v", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("azure: could not upload %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("azure: unexpected status %d while uploading %s", resp.StatusCode, key)
+	}
+
+	return nil
+}
+
+func (r *Resolver) Find(key string) (string, error) {
+	sas, err := r.generateSAS(key)
+	if err != nil {
+		return "", fmt.Errorf("azure: could not generate sas token: %v", err)
+	}
+
+	return fmt.Sprintf("%s?%s", r.blobURL(key), sas), nil
+}
+
+func (r *Resolver) Purge(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, r.blobURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := r.authorize(req); err != nil {
+		return fmt.Errorf("azure: could not sign request: %v", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("azure: could not delete %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("azure: unexpected status %d while deleting %s", resp.StatusCode, key)
+	}
+
+	return nil
+}
+
+func (r *Resolver) Get(key string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, r.blobURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.authorize(req); err != nil {
+		return nil, fmt.Errorf("azure: could not sign request: %v", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("azure: could not download %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("azure: unexpected status %d while downloading %s", resp.StatusCode, key)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// listBlobsResult is the subset of the List Blobs response body this
+// resolver cares about
+type listBlobsResult struct {
+	Blobs struct {
+		Blob []struct {
+			Name string `xml:"Name"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// List enumerates every blob in the container using the List Blobs API
+func (r *Resolver) List() ([]string, error) {
+	var keys []string
+	marker := ""
+
+	for {
+		listURL := fmt.Sprintf("%s/%s?restype=container&comp=list", r.config.Endpoint, r.config.ContainerName)
+		if marker != "" {
+			listURL += "&marker=" + url.QueryEscape(marker)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, listURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.authorize(req); err != nil {
+			return nil, fmt.Errorf("azure: could not sign request: %v", err)
+		}
+
+		resp, err := r.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("azure: could not list container: %v", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("azure: unexpected status %d while listing container", resp.StatusCode)
+		}
+
+		var result listBlobsResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("azure: could not parse list response: %v", err)
+		}
+
+		for _, b := range result.Blobs.Blob {
+			keys = append(keys, b.Name)
+		}
+
+		if result.NextMarker == "" {
+			break
+		}
+
+		marker = result.NextMarker
+	}
+
+	return keys, nil
+}
+
+// authorize attaches either a static SAS token or a Shared Key
+// authorization header to req
+func (r *Resolver) authorize(req *http.Request) error {
+	if r.config.SASToken != "" {
+		q := req.URL.Query()
+		for k, v := range mustParseQuery(r.config.SASToken) {
+			q[k] = v
+		}
+		req.URL.RawQuery = q.Encode()
+
+		return nil
+	}
+
+	now := time.Now().UTC().Format(http.TimeFormat)
+	req.Header.Set("x-ms-date", now)
+	req.Header.Set("x-ms-version", "2021-08-06")
+
+	contentLength := req.Header.Get("Content-Length")
+	if contentLength == "0" {
+		contentLength = ""
+	}
+
+	canonicalizedHeaders := canonicalizeMSHeaders(req.Header)
+	canonicalizedResource := fmt.Sprintf("/%s%s", r.config.AccountName, req.URL.Path)
+
+	stringToSign := fmt.Sprintf(
+		"%s\n\n\n%s\n\n\n\n\n\n\n\n\n%s%s",
+		req.Method,
+		contentLength,
+		canonicalizedHeaders,
+		canonicalizedResource,
+	)
+
+	key, err := base64.StdEncoding.DecodeString(r.config.AccountKey)
+	if err != nil {
+		return fmt.Errorf("invalid account key: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", r.config.AccountName, signature))
+
+	return nil
+}
+
+// generateSAS builds a read-only, time-limited service SAS token for key,
+// signed with the account key
+func (r *Resolver) generateSAS(key string) (string, error) {
+	if r.config.SASToken != "" {
+		return r.config.SASToken, nil
+	}
+
+	accountKey, err := base64.StdEncoding.DecodeString(r.config.AccountKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid account key: %v", err)
+	}
+
+	expiry := time.Now().UTC().Add(time.Duration(r.config.PresignExpire) * time.Second).Format(time.RFC3339)
+
+	const (
+		signedPermissions = "r"
+		signedVersion     = "2021-08-06"
+		signedResource    = "b"
+	)
+
+	canonicalizedResource := fmt.Sprintf(
+		"/blob/%s/%s/%s",
+		r.config.AccountName,
+		r.config.ContainerName,
+		key,
+	)
+
+	stringToSign := sasStringToSign(signedPermissions, expiry, canonicalizedResource, signedVersion, signedResource)
+
+	mac := hmac.New(sha256.New, accountKey)
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	values := url.Values{}
+	values.Set("sp", signedPermissions)
+	values.Set("se", expiry)
+	values.Set("sv", signedVersion)
+	values.Set("sr", signedResource)
+	values.Set("sig", signature)
+
+	return values.Encode(), nil
+}
+
+// sasStringToSign builds the string-to-sign for a service SAS, following
+// the documented field order:
+// https://learn.microsoft.com/rest/api/storageservices/create-service-sas
+// One line per field; fields this resolver doesn't set are left blank.
+func sasStringToSign(signedPermissions, signedExpiry, canonicalizedResource, signedVersion, signedResource string) string {
+	return strings.Join([]string{
+		signedPermissions,
+		"", // signedStart
+		signedExpiry,
+		canonicalizedResource,
+		"", // signedIdentifier
+		"", // signedIP
+		"", // signedProtocol
+		signedVersion,
+		signedResource,
+		"", // signedSnapshotTime
+		"", // signedEncryptionScope
+		"", // rscc (cache-control)
+		"", // rscd (content-disposition)
+		"", // rsce (content-encoding)
+		"", // rscl (content-language)
+		"", // rsct (content-type)
+	}, "\n")
+}
+
+// canonicalizeMSHeaders builds the canonicalized-headers block of a
+// Shared Key signature: every x-ms- header actually present on the
+// request, lowercased and sorted by name, one "name:value\n" line each.
+// Headers only some requests set (e.g. x-ms-blob-type, set by Store but
+// not by Get/Purge/List) must be omitted when absent, or the signature
+// won't match what Azure recomputes from the request actually sent
+func canonicalizeMSHeaders(header http.Header) string {
+	var names []string
+	for name := range header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-ms-") {
+			names = append(names, lower)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(":")
+		b.WriteString(header.Get(name))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func mustParseQuery(raw string) url.Values {
+	v, err := url.ParseQuery(raw)
+	if err != nil {
+		return url.Values{}
+	}
+
+	return v
+}
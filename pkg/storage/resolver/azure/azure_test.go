@@ -0,0 +1,210 @@
+package azure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestResolver(t *testing.T, endpoint string) *Resolver {
+	t.Helper()
+
+	r, err := New(&Config{
+		AccountName:   "myaccount",
+		AccountKey:    "c2VjcmV0LWtleQ==",
+		ContainerName: "my-container",
+		Endpoint:      endpoint,
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	return r
+}
+
+func TestFind_ReturnsURLWithSASSignature(t *testing.T) {
+	r := newTestResolver(t, "https://myaccount.blob.core.windows.net")
+
+	link, err := r.Find("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Find() returned error: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Find() returned an invalid URL: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("sig") == "" {
+		t.Fatal("expected a sig query parameter")
+	}
+
+	if q.Get("sp") != "r" {
+		t.Fatalf("expected read-only permissions, got %s", q.Get("sp"))
+	}
+
+	if !strings.Contains(u.Path, "modules/foo/1.0.0.zip") {
+		t.Fatalf("expected URL path to contain the blob key, got %s", u.Path)
+	}
+}
+
+func TestSASStringToSign_KnownAnswer(t *testing.T) {
+	got := sasStringToSign("r", "2021-01-01T00:00:00Z", "/blob/myaccount/my-container/modules/foo/1.0.0.zip", "2021-08-06", "b")
+
+	want := "r\n\n2021-01-01T00:00:00Z\n/blob/myaccount/my-container/modules/foo/1.0.0.zip\n\n\n\n2021-08-06\nb\n\n\n\n\n\n\n"
+	if got != want {
+		t.Fatalf("unexpected string-to-sign:\ngot:  %q\nwant: %q", got, want)
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret-key"))
+	mac.Write([]byte(got))
+	sig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	const wantSig = "s48qTnMypWU8I2RjOmYi/drCOfPHYHOqtsTzaTjQcHA="
+	if sig != wantSig {
+		t.Fatalf("unexpected signature: got %s, want %s", sig, wantSig)
+	}
+}
+
+func TestStoreAndPurge_SendAuthenticatedRequests(t *testing.T) {
+	var gotMethod string
+	var gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotAuth = r.Header.Get("Authorization")
+
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	if err := r.Store("modules/foo/1.0.0.zip", []byte("content")); err != nil {
+		t.Fatalf("Store() returned error: %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Fatalf("expected a PUT request, got %s", gotMethod)
+	}
+
+	if !strings.HasPrefix(gotAuth, "SharedKey myaccount:") {
+		t.Fatalf("expected a SharedKey Authorization header, got %q", gotAuth)
+	}
+}
+
+func TestPurge_SignsRequestWithoutBlobTypeHeader(t *testing.T) {
+	var gotMethod, gotPath, gotDate, gotVersion, gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotDate = r.Header.Get("x-ms-date")
+		gotVersion = r.Header.Get("x-ms-version")
+		gotAuth = r.Header.Get("Authorization")
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	if err := r.Purge("modules/foo/1.0.0.zip"); err != nil {
+		t.Fatalf("Purge() returned error: %v", err)
+	}
+
+	// Purge never sets x-ms-blob-type (only Store does), so it must be
+	// left out of the canonicalized-headers block entirely, not signed
+	// as an empty line
+	canonicalizedHeaders := fmt.Sprintf("x-ms-date:%s\nx-ms-version:%s\n", gotDate, gotVersion)
+	canonicalizedResource := fmt.Sprintf("/myaccount%s", gotPath)
+	stringToSign := fmt.Sprintf(
+		"%s\n\n\n%s\n\n\n\n\n\n\n\n\n%s%s",
+		gotMethod, "", canonicalizedHeaders, canonicalizedResource,
+	)
+
+	key, err := base64.StdEncoding.DecodeString("c2VjcmV0LWtleQ==")
+	if err != nil {
+		t.Fatalf("could not decode account key: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	wantSig := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	wantAuth := fmt.Sprintf("SharedKey myaccount:%s", wantSig)
+
+	if gotAuth != wantAuth {
+		t.Fatalf("unexpected Authorization header:\ngot:  %q\nwant: %q", gotAuth, wantAuth)
+	}
+}
+
+func TestFind_UsesStaticSASTokenWhenConfigured(t *testing.T) {
+	r, err := New(&Config{
+		AccountName:   "myaccount",
+		SASToken:      "sv=2021-08-06&sp=r&sig=abc123",
+		ContainerName: "my-container",
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	link, err := r.Find("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Find() returned error: %v", err)
+	}
+
+	if !strings.Contains(link, "sig=abc123") {
+		t.Fatalf("expected the static SAS token to be used, got %s", link)
+	}
+}
+
+func TestGet_DownloadsContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("archive content"))
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	content, err := r.Get("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+
+	if string(content) != "archive content" {
+		t.Fatalf("expected archive content, got %q", content)
+	}
+}
+
+func TestList_ParsesListBlobsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<EnumerationResults>
+	<Blobs>
+		<Blob><Name>modules/foo/1.0.0.zip</Name></Blob>
+		<Blob><Name>modules/bar/2.0.0.zip</Name></Blob>
+	</Blobs>
+</EnumerationResults>`))
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	keys, err := r.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "modules/foo/1.0.0.zip" || keys[1] != "modules/bar/2.0.0.zip" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
@@ -0,0 +1,55 @@
+package factory
+
+import (
+	"fmt"
+
+	"terralist/pkg/storage/resolver"
+	"terralist/pkg/storage/resolver/azure"
+	"terralist/pkg/storage/resolver/gcs"
+	"terralist/pkg/storage/resolver/local"
+	"terralist/pkg/storage/resolver/proxy"
+	"terralist/pkg/storage/resolver/s3"
+)
+
+// NewResolver creates a new resolver.Resolver based on the given resolver type
+func NewResolver(resolverType resolver.ResolverType, config any) (resolver.Resolver, error) {
+	switch resolverType {
+	case resolver.PROXY:
+		cfg, ok := config.(*proxy.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected proxy.Config, got %T", config)
+		}
+
+		return proxy.New(cfg)
+	case resolver.LOCAL:
+		cfg, ok := config.(*local.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected local.Config, got %T", config)
+		}
+
+		return local.New(cfg)
+	case resolver.S3:
+		cfg, ok := config.(*s3.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected s3.Config, got %T", config)
+		}
+
+		return s3.New(cfg)
+	case resolver.AZURE:
+		cfg, ok := config.(*azure.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected azure.Config, got %T", config)
+		}
+
+		return azure.New(cfg)
+	case resolver.GCS:
+		cfg, ok := config.(*gcs.Config)
+		if !ok {
+			return nil, fmt.Errorf("expected gcs.Config, got %T", config)
+		}
+
+		return gcs.New(cfg)
+	default:
+		return nil, fmt.Errorf("unknown resolver type %q", resolverType)
+	}
+}
@@ -0,0 +1,237 @@
+package gcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Config holds the configuration for the Google Cloud Storage resolver
+type Config struct {
+	HomeDirectory string
+
+	BucketName string
+
+	// CredentialsFile is a path to a service account JSON key file. It is
+	// required, since signed URLs can only be generated with a service
+	// account's private key
+	CredentialsFile string
+
+	// PresignExpire is how long, in seconds, signed download URLs remain
+	// valid
+	PresignExpire int
+
+	// Endpoint overrides the default Google Cloud Storage JSON API
+	// endpoint, for compatible emulators or tests
+	Endpoint string
+}
+
+// Resolver implements resolver.Resolver by storing archives in a Google
+// Cloud Storage bucket and serving them through signed, time-limited URLs
+type Resolver struct {
+	config *Config
+	sa     *serviceAccount
+
+	httpClient *http.Client
+}
+
+// New creates a new GCS resolver
+func New(config *Config) (*Resolver, error) {
+	if config.BucketName == "" {
+		return nil, fmt.Errorf("gcs: bucket name is required")
+	}
+
+	if config.CredentialsFile == "" {
+		return nil, fmt.Errorf("gcs: a credentials file is required")
+	}
+
+	if config.PresignExpire <= 0 {
+		config.PresignExpire = 900
+	}
+
+	if config.Endpoint == "" {
+		config.Endpoint = "https://storage.googleapis.com"
+	}
+
+	sa, err := loadServiceAccount(config.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not load credentials: %v", err)
+	}
+
+	return &Resolver{
+		config:     config,
+		sa:         sa,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// objectURL returns the URL of key in the bucket
+func (r *Resolver) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", r.config.Endpoint, r.config.BucketName, url.PathEscape(key))
+}
+
+func (r *Resolver) Store(key string, content []byte) error {
+	token, err := r.accessToken(time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("gcs: could not obtain access token: %v", err)
+	}
+
+	uploadURL := fmt.Sprintf(
+		"%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		r.config.Endpoint, r.config.BucketName, url.QueryEscape(key),
+	)
+
+	req, err := http.NewRequest(http.MethodPost, uploadURL, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gcs: could not upload %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gcs: unexpected status %d while uploading %s", resp.StatusCode, key)
+	}
+
+	return nil
+}
+
+func (r *Resolver) Find(key string) (string, error) {
+	expire := time.Duration(r.config.PresignExpire) * time.Second
+
+	return r.presignURL(http.MethodGet, r.objectURL(key), expire, time.Now().UTC())
+}
+
+func (r *Resolver) Purge(key string) error {
+	token, err := r.accessToken(time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("gcs: could not obtain access token: %v", err)
+	}
+
+	deleteURL := fmt.Sprintf(
+		"%s/storage/v1/b/%s/o/%s",
+		r.config.Endpoint, r.config.BucketName, url.QueryEscape(key),
+	)
+
+	req, err := http.NewRequest(http.MethodDelete, deleteURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gcs: could not delete %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("gcs: unexpected status %d while deleting %s", resp.StatusCode, key)
+	}
+
+	return nil
+}
+
+func (r *Resolver) Get(key string) ([]byte, error) {
+	token, err := r.accessToken(time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not obtain access token: %v", err)
+	}
+
+	downloadURL := fmt.Sprintf(
+		"%s/storage/v1/b/%s/o/%s?alt=media",
+		r.config.Endpoint, r.config.BucketName, url.QueryEscape(key),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not download %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gcs: unexpected status %d while downloading %s", resp.StatusCode, key)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// listObjectsResult is the subset of the Objects: list response body this
+// resolver cares about
+type listObjectsResult struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// List enumerates every object in the bucket using the JSON API
+func (r *Resolver) List() ([]string, error) {
+	token, err := r.accessToken(time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not obtain access token: %v", err)
+	}
+
+	var keys []string
+	pageToken := ""
+
+	for {
+		listURL := fmt.Sprintf("%s/storage/v1/b/%s/o", r.config.Endpoint, r.config.BucketName)
+		if pageToken != "" {
+			listURL += "?pageToken=" + url.QueryEscape(pageToken)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, listURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := r.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("gcs: could not list bucket: %v", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("gcs: unexpected status %d while listing bucket", resp.StatusCode)
+		}
+
+		var result listObjectsResult
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("gcs: could not parse list response: %v", err)
+		}
+
+		for _, item := range result.Items {
+			keys = append(keys, item.Name)
+		}
+
+		if result.NextPageToken == "" {
+			break
+		}
+
+		pageToken = result.NextPageToken
+	}
+
+	return keys, nil
+}
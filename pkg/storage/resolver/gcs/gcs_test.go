@@ -0,0 +1,202 @@
+package gcs
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestCredentials(t *testing.T, tokenURI string) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("could not generate rsa key: %v", err)
+	}
+
+	pemKey := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	creds, err := json.Marshal(map[string]string{
+		"client_email": "test@my-project.iam.gserviceaccount.com",
+		"private_key":  string(pemKey),
+		"token_uri":    tokenURI,
+	})
+	if err != nil {
+		t.Fatalf("could not marshal credentials: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, creds, 0o600); err != nil {
+		t.Fatalf("could not write credentials file: %v", err)
+	}
+
+	return path
+}
+
+func TestFind_ReturnsSignedURL(t *testing.T) {
+	credentials := writeTestCredentials(t, "https://oauth2.example.com/token")
+
+	r, err := New(&Config{
+		BucketName:      "my-bucket",
+		CredentialsFile: credentials,
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	link, err := r.Find("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Find() returned error: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Find() returned an invalid URL: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("X-Goog-Signature") == "" {
+		t.Fatal("expected an X-Goog-Signature query parameter")
+	}
+
+	if !strings.Contains(u.Path, "modules/foo/1.0.0.zip") {
+		t.Fatalf("expected URL path to contain the object key, got %s", u.Path)
+	}
+}
+
+func TestStoreAndPurge_UseBearerTokenFromTokenEndpoint(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	credentials := writeTestCredentials(t, tokenServer.URL)
+
+	var gotAuth string
+	var gotMethod string
+
+	storageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotMethod = r.Method
+
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer storageServer.Close()
+
+	r, err := New(&Config{
+		BucketName:      "my-bucket",
+		CredentialsFile: credentials,
+		Endpoint:        storageServer.URL,
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if err := r.Store("modules/foo/1.0.0.zip", []byte("content")); err != nil {
+		t.Fatalf("Store() returned error: %v", err)
+	}
+
+	if gotAuth != "Bearer test-token" {
+		t.Fatalf("expected the bearer token from the token endpoint, got %q", gotAuth)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Fatalf("expected a POST request, got %s", gotMethod)
+	}
+
+	if err := r.Purge("modules/foo/1.0.0.zip"); err != nil {
+		t.Fatalf("Purge() returned error: %v", err)
+	}
+
+	if gotMethod != http.MethodDelete {
+		t.Fatalf("expected a DELETE request, got %s", gotMethod)
+	}
+}
+
+func TestGet_DownloadsContentUsingBearerToken(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	credentials := writeTestCredentials(t, tokenServer.URL)
+
+	var gotAuth string
+
+	storageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte("archive content"))
+	}))
+	defer storageServer.Close()
+
+	r, err := New(&Config{
+		BucketName:      "my-bucket",
+		CredentialsFile: credentials,
+		Endpoint:        storageServer.URL,
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	content, err := r.Get("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+
+	if string(content) != "archive content" {
+		t.Fatalf("expected archive content, got %q", content)
+	}
+
+	if gotAuth != "Bearer test-token" {
+		t.Fatalf("expected the bearer token from the token endpoint, got %q", gotAuth)
+	}
+}
+
+func TestList_ParsesObjectsResponse(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-token","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	credentials := writeTestCredentials(t, tokenServer.URL)
+
+	storageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"items":[{"name":"modules/foo/1.0.0.zip"},{"name":"modules/bar/2.0.0.zip"}]}`))
+	}))
+	defer storageServer.Close()
+
+	r, err := New(&Config{
+		BucketName:      "my-bucket",
+		CredentialsFile: credentials,
+		Endpoint:        storageServer.URL,
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	keys, err := r.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "modules/foo/1.0.0.zip" || keys[1] != "modules/bar/2.0.0.zip" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
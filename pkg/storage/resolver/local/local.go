@@ -0,0 +1,86 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds the configuration for the local resolver
+type Config struct {
+	// HomeDirectory is where archives are stored on disk
+	HomeDirectory string
+}
+
+// Resolver implements resolver.Resolver by storing archives on the local
+// filesystem, under HomeDirectory
+type Resolver struct {
+	config *Config
+}
+
+// New creates a new local resolver
+func New(config *Config) (*Resolver, error) {
+	if config.HomeDirectory == "" {
+		return nil, fmt.Errorf("local: home directory is required")
+	}
+
+	return &Resolver{config: config}, nil
+}
+
+func (r *Resolver) path(key string) string {
+	return filepath.Join(r.config.HomeDirectory, key)
+}
+
+func (r *Resolver) Store(key string, content []byte) error {
+	path := r.path(key)
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("could not create directory for %s: %v", key, err)
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
+
+func (r *Resolver) Find(key string) (string, error) {
+	return r.path(key), nil
+}
+
+func (r *Resolver) Purge(key string) error {
+	return os.Remove(r.path(key))
+}
+
+func (r *Resolver) Get(key string) ([]byte, error) {
+	return os.ReadFile(r.path(key))
+}
+
+// List walks HomeDirectory and returns every stored key, relative to it
+func (r *Resolver) List() ([]string, error) {
+	var keys []string
+
+	err := filepath.WalkDir(r.config.HomeDirectory, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		key, err := filepath.Rel(r.config.HomeDirectory, path)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list %s: %v", r.config.HomeDirectory, err)
+	}
+
+	return keys, nil
+}
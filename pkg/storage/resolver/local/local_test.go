@@ -0,0 +1,63 @@
+package local
+
+import (
+	"testing"
+)
+
+func TestStoreGetList_RoundTripsArchives(t *testing.T) {
+	r, err := New(&Config{HomeDirectory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if err := r.Store("modules/foo/1.0.0.zip", []byte("content")); err != nil {
+		t.Fatalf("Store() returned error: %v", err)
+	}
+
+	content, err := r.Get("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+
+	if string(content) != "content" {
+		t.Fatalf("expected content, got %q", content)
+	}
+
+	keys, err := r.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(keys) != 1 || keys[0] != "modules/foo/1.0.0.zip" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+
+	if err := r.Purge("modules/foo/1.0.0.zip"); err != nil {
+		t.Fatalf("Purge() returned error: %v", err)
+	}
+
+	keys, err = r.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(keys) != 0 {
+		t.Fatalf("expected no keys after purge, got %v", keys)
+	}
+}
+
+func TestList_ReturnsNoKeysForMissingHomeDirectory(t *testing.T) {
+	r, err := New(&Config{HomeDirectory: t.TempDir() + "/does-not-exist"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	keys, err := r.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v", keys)
+	}
+}
@@ -0,0 +1,42 @@
+package resolver
+
+// ResolverType identifies a supported storage resolver implementation
+type ResolverType string
+
+const (
+	PROXY ResolverType = "PROXY"
+	LOCAL ResolverType = "LOCAL"
+	S3    ResolverType = "S3"
+	AZURE ResolverType = "AZURE"
+	GCS   ResolverType = "GCS"
+)
+
+// Resolver abstracts the storage backend used to serve module and provider
+// archives
+type Resolver interface {
+	// Store uploads an archive and returns the key it was stored under
+	Store(key string, content []byte) error
+
+	// Find returns a URL the client can use to download the archive
+	// stored under key. Depending on the implementation, this can be a
+	// presigned, time-limited URL or a direct link served by Terralist
+	// itself
+	Find(key string) (string, error)
+
+	// Purge removes the archive stored under key
+	Purge(key string) error
+}
+
+// Lister is implemented by resolvers that can enumerate every key they
+// currently store, such as the ones backing the migrate command. The
+// proxy resolver, which never persists anything, does not implement it
+type Lister interface {
+	List() ([]string, error)
+}
+
+// Getter is implemented by resolvers that can read back the content
+// stored under a key, as opposed to just a download link. The proxy
+// resolver does not implement it
+type Getter interface {
+	Get(key string) ([]byte, error)
+}
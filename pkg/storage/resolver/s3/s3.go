@@ -0,0 +1,222 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Config holds the configuration for the S3 resolver
+type Config struct {
+	HomeDirectory string
+
+	BucketName      string
+	BucketRegion    string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// LinkExpire is how long, in seconds, presigned download URLs remain
+	// valid
+	LinkExpire int
+
+	// Endpoint overrides the default AWS endpoint, for S3-compatible
+	// storage (e.g. MinIO) or for tests. Path-style addressing is used
+	// when set, instead of virtual-hosted-style
+	Endpoint string
+}
+
+// Resolver implements resolver.Resolver by storing archives in an S3
+// bucket and serving them through presigned, time-limited URLs
+type Resolver struct {
+	config *Config
+	signer *signer
+
+	httpClient *http.Client
+}
+
+// New creates a new S3 resolver
+func New(config *Config) (*Resolver, error) {
+	if config.BucketName == "" {
+		return nil, fmt.Errorf("s3: bucket name is required")
+	}
+
+	if config.BucketRegion == "" {
+		return nil, fmt.Errorf("s3: bucket region is required")
+	}
+
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3: access key id and secret access key are required")
+	}
+
+	if config.LinkExpire <= 0 {
+		config.LinkExpire = 900
+	}
+
+	return &Resolver{
+		config: config,
+		signer: &signer{
+			accessKeyID:     config.AccessKeyID,
+			secretAccessKey: config.SecretAccessKey,
+			region:          config.BucketRegion,
+		},
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// bucketURL returns the URL of the bucket itself, using path-style
+// addressing against Endpoint when configured, or the standard
+// virtual-hosted-style AWS URL otherwise
+func (r *Resolver) bucketURL() string {
+	if r.config.Endpoint != "" {
+		return fmt.Sprintf("%s/%s", r.config.Endpoint, r.config.BucketName)
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", r.config.BucketName, r.config.BucketRegion)
+}
+
+// objectURL returns the URL of key in the bucket
+func (r *Resolver) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s", r.bucketURL(), key)
+}
+
+func (r *Resolver) Store(key string, content []byte) error {
+	now := time.Now().UTC()
+
+	req, err := http.NewRequest(http.MethodPut, r.objectURL(key), bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	r.signer.signRequest(req, sha256Hex(string(content)), now)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3: could not upload %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("s3: unexpected status %d while uploading %s", resp.StatusCode, key)
+	}
+
+	return nil
+}
+
+func (r *Resolver) Find(key string) (string, error) {
+	expire := time.Duration(r.config.LinkExpire) * time.Second
+
+	return r.signer.presignURL(http.MethodGet, r.objectURL(key), expire, time.Now().UTC())
+}
+
+func (r *Resolver) Purge(key string) error {
+	now := time.Now().UTC()
+
+	req, err := http.NewRequest(http.MethodDelete, r.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	r.signer.signRequest(req, sha256Hex(""), now)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3: could not delete %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("s3: unexpected status %d while deleting %s", resp.StatusCode, key)
+	}
+
+	return nil
+}
+
+func (r *Resolver) Get(key string) ([]byte, error) {
+	now := time.Now().UTC()
+
+	req, err := http.NewRequest(http.MethodGet, r.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.signer.signRequest(req, sha256Hex(""), now)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: could not download %s: %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("s3: unexpected status %d while downloading %s", resp.StatusCode, key)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// listBucketResult is the subset of the ListObjectsV2 response body this
+// resolver cares about
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated   bool   `xml:"IsTruncated"`
+	NextContToken string `xml:"NextContinuationToken"`
+}
+
+// List enumerates every object in the bucket using the ListObjectsV2 API
+func (r *Resolver) List() ([]string, error) {
+	var keys []string
+	continuationToken := ""
+
+	for {
+		listURL := fmt.Sprintf("%s?list-type=2", r.bucketURL())
+		if continuationToken != "" {
+			listURL += "&continuation-token=" + continuationToken
+		}
+
+		now := time.Now().UTC()
+
+		req, err := http.NewRequest(http.MethodGet, listURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		r.signer.signRequest(req, sha256Hex(""), now)
+
+		resp, err := r.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("s3: could not list bucket: %v", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("s3: unexpected status %d while listing bucket", resp.StatusCode)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("s3: could not parse list response: %v", err)
+		}
+
+		for _, c := range result.Contents {
+			keys = append(keys, c.Key)
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+
+		continuationToken = result.NextContToken
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,131 @@
+package s3
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestResolver(t *testing.T, endpoint string) *Resolver {
+	t.Helper()
+
+	r, err := New(&Config{
+		BucketName:      "my-bucket",
+		BucketRegion:    "us-east-1",
+		AccessKeyID:     "AKIAEXAMPLE",
+		SecretAccessKey: "secret",
+		Endpoint:        endpoint,
+	})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	return r
+}
+
+func TestFind_ReturnsPresignedURLWithSignature(t *testing.T) {
+	r := newTestResolver(t, "https://s3.example.com")
+
+	link, err := r.Find("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Find() returned error: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Find() returned an invalid URL: %v", err)
+	}
+
+	q := u.Query()
+	if q.Get("X-Amz-Signature") == "" {
+		t.Fatal("expected a X-Amz-Signature query parameter")
+	}
+
+	if q.Get("X-Amz-Expires") != "900" {
+		t.Fatalf("expected default expiry of 900 seconds, got %s", q.Get("X-Amz-Expires"))
+	}
+
+	if !strings.Contains(u.Path, "modules/foo/1.0.0.zip") {
+		t.Fatalf("expected URL path to contain the object key, got %s", u.Path)
+	}
+}
+
+func TestStoreAndPurge_SendAuthenticatedRequests(t *testing.T) {
+	var gotMethod string
+	var gotAuth string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotAuth = r.Header.Get("Authorization")
+
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	if err := r.Store("modules/foo/1.0.0.zip", []byte("content")); err != nil {
+		t.Fatalf("Store() returned error: %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Fatalf("expected a PUT request, got %s", gotMethod)
+	}
+
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 ") {
+		t.Fatalf("expected a SigV4 Authorization header, got %q", gotAuth)
+	}
+
+	if err := r.Purge("modules/foo/1.0.0.zip"); err != nil {
+		t.Fatalf("Purge() returned error: %v", err)
+	}
+
+	if gotMethod != http.MethodPut && gotMethod != http.MethodDelete {
+		t.Fatalf("unexpected method %s", gotMethod)
+	}
+}
+
+func TestGet_DownloadsContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("archive content"))
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	content, err := r.Get("modules/foo/1.0.0.zip")
+	if err != nil {
+		t.Fatalf("Get() returned error: %v", err)
+	}
+
+	if string(content) != "archive content" {
+		t.Fatalf("expected archive content, got %q", content)
+	}
+}
+
+func TestList_ParsesListObjectsResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult>
+	<Contents><Key>modules/foo/1.0.0.zip</Key></Contents>
+	<Contents><Key>modules/bar/2.0.0.zip</Key></Contents>
+	<IsTruncated>false</IsTruncated>
+</ListBucketResult>`))
+	}))
+	defer server.Close()
+
+	r := newTestResolver(t, server.URL)
+
+	keys, err := r.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(keys) != 2 || keys[0] != "modules/foo/1.0.0.zip" || keys[1] != "modules/bar/2.0.0.zip" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+}
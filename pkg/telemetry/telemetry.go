@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config holds the configuration needed to enable observability
+type Config struct {
+	// MetricsEnabled mounts a Prometheus /metrics handler
+	MetricsEnabled bool
+
+	// OTLPEndpoint is the collector Terralist exports traces to. Tracing
+	// is disabled when empty
+	OTLPEndpoint string
+
+	// ServiceName identifies this instance in exported traces and metrics
+	ServiceName string
+
+	// SampleRatio is the fraction, between 0 and 1, of traces to sample
+	SampleRatio float64
+}
+
+// Telemetry bundles the Prometheus registry and OpenTelemetry tracer
+// provider used to instrument HTTP handlers, database queries and
+// storage resolver calls
+type Telemetry struct {
+	config *Config
+
+	Registry       *prometheus.Registry
+	TracerProvider trace.TracerProvider
+}
+
+// New builds the Prometheus registry and, if config.OTLPEndpoint is set,
+// an OTLP tracer provider for config.ServiceName, sampled at
+// config.SampleRatio
+func New(config *Config) (*Telemetry, error) {
+	t := &Telemetry{
+		config:         config,
+		Registry:       prometheus.NewRegistry(),
+		TracerProvider: trace.NewNoopTracerProvider(),
+	}
+
+	if config.OTLPEndpoint != "" {
+		tp, err := newOTLPTracerProvider(config)
+		if err != nil {
+			return nil, err
+		}
+
+		t.TracerProvider = tp
+	}
+
+	return t, nil
+}
+
+// Handler returns the HTTP handler that should be mounted at /metrics. It
+// responds 404 when config.MetricsEnabled is false, so operators can opt
+// out of exposing the endpoint entirely
+func (t *Telemetry) Handler() http.Handler {
+	if !t.config.MetricsEnabled {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.NotFound(w, r)
+		})
+	}
+
+	return promhttp.HandlerFor(t.Registry, promhttp.HandlerOpts{})
+}
+
+// Shutdown flushes any buffered spans. Safe to call even when tracing was
+// never enabled
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	if sd, ok := t.TracerProvider.(interface{ Shutdown(context.Context) error }); ok {
+		return sd.Shutdown(ctx)
+	}
+
+	return nil
+}
+
+// newOTLPTracerProvider builds an OTLP-over-HTTP exporter pointed at
+// config.OTLPEndpoint and wraps it in a TracerProvider, resourced with
+// config.ServiceName and sampled at config.SampleRatio
+func newOTLPTracerProvider(config *Config) (trace.TracerProvider, error) {
+	ctx := context.Background()
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(config.OTLPEndpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+	), nil
+}
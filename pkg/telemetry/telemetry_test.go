@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_DisabledByDefault(t *testing.T) {
+	tel, err := New(&Config{ServiceName: "terralist"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	tel.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected metrics to be disabled by default, got status %d", rec.Code)
+	}
+}
+
+func TestHandler_EnabledServesPrometheusFormat(t *testing.T) {
+	tel, err := New(&Config{ServiceName: "terralist", MetricsEnabled: true})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	tel.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected metrics to be served, got status %d", rec.Code)
+	}
+}
+
+func TestShutdown_NoopWhenOTLPEndpointEmpty(t *testing.T) {
+	tel, err := New(&Config{ServiceName: "terralist"})
+	if err != nil {
+		t.Fatalf("New() returned error: %v", err)
+	}
+
+	if err := tel.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() returned error: %v", err)
+	}
+}